@@ -0,0 +1,52 @@
+// Command libpowertelemetry exposes the power package to C, Objective-C and
+// Swift hosts. Build it as a static archive and header with:
+//
+//	go build -buildmode=c-archive -o libpowertelemetry.a ./cmd/libpowertelemetry
+//
+// and link libpowertelemetry.a (plus the CoreFoundation and IOKit frameworks)
+// into the host app.
+//
+// Memory ownership: every string returned by this library is allocated with
+// malloc and owned by the caller, who must release it exactly once with
+// PowerTelemetryFree (or free). Strings passed in are never retained.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"unsafe"
+
+	"github.com/peterneutron/go-iokit-powertelemetry/power"
+)
+
+// GetBatteryInfoJSON reads a battery snapshot and returns it as a
+// NUL-terminated JSON object with the same shape as power.BatteryInfo. It
+// returns NULL if the read fails. The caller owns the returned string and must
+// release it with PowerTelemetryFree.
+//
+//export GetBatteryInfoJSON
+func GetBatteryInfoJSON() *C.char {
+	info, err := power.GetBatteryInfo()
+	if err != nil {
+		return nil
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil
+	}
+	return C.CString(string(data))
+}
+
+// PowerTelemetryFree releases a string returned by this library. Passing NULL
+// is a no-op.
+//
+//export PowerTelemetryFree
+func PowerTelemetryFree(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+// main is required by -buildmode=c-archive and is never called.
+func main() {}
@@ -0,0 +1,92 @@
+package power
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RoleSink is the Adapter.Role of a port the Mac is drawing power through,
+// named as in USB Power Delivery.
+const RoleSink = "sink"
+
+// adapterRole maps what AppleSmartBattery reports onto Adapter.Role.
+func adapterRole(sinking bool) string {
+	if sinking {
+		return RoleSink
+	}
+	return ""
+}
+
+// EffectiveWatts returns the adapter's power rating in Watts. It is MaxWatts
+// when the adapter reported one, and otherwise MaxVoltage × MaxAmperage
+// rounded to the nearest Watt, for adapters that only report the negotiated
+// voltage and current. It returns 0 when neither is available.
+func (a Adapter) EffectiveWatts() int {
+	if a.MaxWatts != 0 {
+		return a.MaxWatts
+	}
+	return int(math.Round(a.MaxVoltage * a.MaxAmperage))
+}
+
+// AdapterHeadroom returns how many Watts the adapter could supply beyond what
+// it is supplying now, as MaxWatts − ACPower. A positive value means the
+// charger is big enough for the current load; once charging completes or is
+// limited, the adapter only supplies what the system needs and the headroom
+// grows. It returns 0 when the adapter's rating is unknown.
+//
+// The rating (Adapter.EffectiveWatts) is recorded, unexported, when the
+// Calculations are derived by a read, ParseIORegDump or Recalculate. It
+// isn't serialized, so Calculations decoded from JSON or built by hand have
+// no rating and AdapterHeadroom returns 0 for them until Recalculate is
+// called on their BatteryInfo.
+func (c Calculations) AdapterHeadroom() float64 {
+	if c.adapterWatts <= 0 {
+		return 0
+	}
+	return float64(c.adapterWatts) - c.ACPower
+}
+
+// descriptionWords are words NormalizedDescription spells specially rather
+// than just capitalizing.
+var descriptionWords = map[string]string{
+	"ac": "AC", "dc": "DC", "pd": "PD", "pps": "PPS", "usb": "USB",
+	"magsafe": "MagSafe",
+}
+
+// descriptionAliases maps spellings of the same adapter kind, after
+// lower-casing and collapsing spaces, onto one form.
+var descriptionAliases = map[string]string{
+	"usbc charger":   "usb-c charger",
+	"usb c charger":  "usb-c charger",
+	"usb-pd charger": "pd charger",
+	"usb pd charger": "pd charger",
+}
+
+// NormalizedDescription returns Description in a canonical form for display
+// and grouping, e.g. "PD Charger" for "pd charger" and "PD Charger", or
+// "USB-C Charger" for "usb c charger". Acronyms such as USB and PD are
+// upper-cased, MagSafe keeps Apple's spelling, and other words get a
+// capital first letter but otherwise keep their case, so "95W USB-C Power
+// Adapter" is unchanged. Description itself is left as reported. It
+// returns "" when Description is empty.
+func (a Adapter) NormalizedDescription() string {
+	desc := strings.Join(strings.Fields(a.Description), " ")
+	if alias, ok := descriptionAliases[strings.ToLower(desc)]; ok {
+		desc = alias
+	}
+	words := strings.Split(desc, " ")
+	for i, word := range words {
+		parts := strings.Split(word, "-")
+		for j, part := range parts {
+			if spelling, ok := descriptionWords[strings.ToLower(part)]; ok {
+				parts[j] = spelling
+			} else if r, size := utf8.DecodeRuneInString(part); size > 0 {
+				parts[j] = string(unicode.ToUpper(r)) + part[size:]
+			}
+		}
+		words[i] = strings.Join(parts, "-")
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,71 @@
+package power
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNormalizedDescription(t *testing.T) {
+	tests := []struct {
+		desc, want string
+	}{
+		{"", ""},
+		{"pd charger", "PD Charger"},
+		{"PD Charger", "PD Charger"},
+		{"  pd   charger ", "PD Charger"},
+		{"usb c charger", "USB-C Charger"},
+		{"USBC Charger", "USB-C Charger"},
+		{"usb-pd charger", "PD Charger"},
+		{"magsafe 3", "MagSafe 3"},
+		{"95W USB-C Power Adapter", "95W USB-C Power Adapter"},
+		{"140W usb-c power adapter", "140W USB-C Power Adapter"},
+		{"éco charger", "Éco Charger"},
+	}
+	for _, tt := range tests {
+		if got := (Adapter{Description: tt.desc}).NormalizedDescription(); got != tt.want {
+			t.Errorf("NormalizedDescription(%q) = %q, want %q", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestAdapterHeadroom(t *testing.T) {
+	tests := []struct {
+		name    string
+		adapter Adapter
+		want    float64
+	}{
+		{"no adapter", Adapter{}, 0},
+		{"rated", Adapter{MaxWatts: 96, InputVoltage: 20, InputAmperage: 2}, 56},
+		{"from voltage and current", Adapter{MaxVoltage: 20, MaxAmperage: 3, InputVoltage: 20, InputAmperage: 1.5}, 30},
+		{"overdrawn", Adapter{MaxWatts: 30, InputVoltage: 20, InputAmperage: 2}, -10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &BatteryInfo{Adapter: tt.adapter}
+			info.Recalculate(Options{})
+			if got := info.Calculations.AdapterHeadroom(); got != tt.want {
+				t.Errorf("AdapterHeadroom() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdapterHeadroomJSONRoundTrip(t *testing.T) {
+	info := &BatteryInfo{Adapter: Adapter{MaxWatts: 96, InputVoltage: 20, InputAmperage: 2}}
+	info.Recalculate(Options{})
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded BatteryInfo
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded.Calculations.AdapterHeadroom(); got != 0 {
+		t.Errorf("AdapterHeadroom() after decoding = %v, want 0", got)
+	}
+	decoded.Recalculate(Options{})
+	if got := decoded.Calculations.AdapterHeadroom(); got != 56 {
+		t.Errorf("AdapterHeadroom() after Recalculate = %v, want 56", got)
+	}
+}
@@ -0,0 +1,79 @@
+package power
+
+import (
+	"math"
+	"sort"
+)
+
+// HealthSummary aggregates snapshots from a fleet of machines.
+type HealthSummary struct {
+	// Batteries is how many snapshots were aggregated. Snapshots contributed
+	// by machines without a battery (nil, or with no DesignCapacity) are
+	// counted in Skipped instead.
+	Batteries int
+	Skipped   int
+
+	// Health statistics over HealthByMaxCapacityFloat, in percent.
+	MeanHealth   float64
+	MedianHealth float64
+	P90Health    float64 // 90% of batteries are at or below this health
+
+	// Cycle count distribution.
+	MinCycles    int
+	MedianCycles int
+	P90Cycles    int // 90% of batteries are at or below this count
+	MaxCycles    int
+
+	// NeedingService counts batteries for which WarrantyFlag recommends
+	// service.
+	NeedingService int
+}
+
+// AggregateHealth computes fleet-wide health and cycle statistics so that
+// dashboards built on this package agree on their figures. Percentiles use
+// the nearest-rank method. With no batteries to aggregate, every statistic
+// is zero.
+func AggregateHealth(snaps []*BatteryInfo) HealthSummary {
+	var summary HealthSummary
+	var health []float64
+	var cycles []int
+	for _, snap := range snaps {
+		if snap == nil || snap.Battery.DesignCapacity <= 0 {
+			summary.Skipped++
+			continue
+		}
+		health = append(health, snap.Calculations.HealthByMaxCapacityFloat)
+		cycles = append(cycles, snap.Battery.CycleCount)
+		if needsService, _ := snap.WarrantyFlag(); needsService {
+			summary.NeedingService++
+		}
+	}
+	summary.Batteries = len(health)
+	if summary.Batteries == 0 {
+		return summary
+	}
+
+	sort.Float64s(health)
+	sort.Ints(cycles)
+
+	var sum float64
+	for _, h := range health {
+		sum += h
+	}
+	summary.MeanHealth = sum / float64(len(health))
+	summary.MedianHealth = health[nearestRank(len(health), 50)]
+	summary.P90Health = health[nearestRank(len(health), 90)]
+
+	summary.MinCycles = cycles[0]
+	summary.MedianCycles = cycles[nearestRank(len(cycles), 50)]
+	summary.P90Cycles = cycles[nearestRank(len(cycles), 90)]
+	summary.MaxCycles = cycles[len(cycles)-1]
+	return summary
+}
+
+// nearestRank returns the index of the p-th percentile in a sorted slice of
+// n > 0 elements.
+func nearestRank(n int, p float64) int {
+	rank := int(math.Ceil(p / 100 * float64(n)))
+	return min(max(rank, 1), n) - 1
+}
@@ -0,0 +1,75 @@
+package power
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// alarmInterval is how often AlarmBelow samples the battery. Charge
+	// moves by at most a percent or two a minute, so this is ample.
+	alarmInterval = 30 * time.Second
+
+	// alarmHysteresis is how many percentage points above the threshold the
+	// charge must climb before AlarmBelow re-arms, so that a reading
+	// wobbling around the threshold fires only once.
+	alarmHysteresis = 2
+
+	// healthAlarmInterval is OnHealthBelow's poll interval when none is
+	// given. Health moves over weeks, so there is no point sampling faster.
+	healthAlarmInterval = 10 * time.Minute
+)
+
+// AlarmBelow calls fn once when the state of charge drops below percent while
+// the battery is discharging, and then not again until the charge has climbed
+// back to at least percent plus a small hysteresis margin. If the charge is
+// already below percent and discharging when AlarmBelow starts, fn is called
+// on the first reading.
+//
+// AlarmBelow returns immediately; readings are taken in the background (see
+// Watch) and fn is called from that goroutine with the snapshot that crossed
+// the threshold. Failed reads are skipped. It stops once ctx is done.
+func AlarmBelow(ctx context.Context, percent int, fn func(*BatteryInfo)) {
+	snapshots, _ := Watch(ctx, alarmInterval)
+	go func() {
+		armed := true
+		for info := range snapshots {
+			charge := info.chargePercent()
+			switch {
+			case armed && charge < percent && info.appleAmperage() < 0:
+				armed = false
+				fn(info)
+			case !armed && charge >= percent+alarmHysteresis:
+				armed = true
+			}
+		}
+	}()
+}
+
+// OnHealthBelow calls fn once, the first time Calculations.ConditionAdjustedHealth
+// is read below percent, and then stops polling. Health only falls over the
+// life of a pack, apart from the occasional gauge recalibration, so unlike
+// AlarmBelow it never re-arms. Snapshots without a DesignCapacity, which have
+// no health figure, are ignored.
+//
+// The battery is read every interval; a non-positive interval means ten
+// minutes. OnHealthBelow returns immediately and calls fn from a background
+// goroutine, like AlarmBelow. Failed reads are skipped. It stops once ctx is
+// done or fn has been called.
+func OnHealthBelow(ctx context.Context, percent int, interval time.Duration, fn func(*BatteryInfo)) {
+	if interval <= 0 {
+		interval = healthAlarmInterval
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	snapshots, _ := Watch(ctx, interval)
+	go func() {
+		defer cancel()
+		for info := range snapshots {
+			if info.Battery.DesignCapacity > 0 && info.Calculations.ConditionAdjustedHealth < percent {
+				cancel()
+				fn(info)
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,28 @@
+package power
+
+// Assertion describes a power assertion held by a process, such as one
+// preventing idle sleep.
+type Assertion struct {
+	// PID is the process holding the assertion.
+	PID int
+
+	// Process is the holding process's name, when reported.
+	Process string
+
+	// Type is the assertion type (e.g., "PreventUserIdleSystemSleep").
+	Type string
+
+	// Name is the human-readable reason the process gave.
+	Name string
+
+	// Level is 255 (kIOPMAssertionLevelOn) while the assertion is held.
+	Level int
+}
+
+// ActiveAssertions returns the power assertions currently held by all
+// processes, via IOPMCopyAssertionsByProcess. It explains why the battery drains
+// while the machine looks idle. It is a read-only query and safe for
+// concurrent use.
+func ActiveAssertions() ([]Assertion, error) {
+	return copyActiveAssertions()
+}
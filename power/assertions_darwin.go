@@ -0,0 +1,96 @@
+package power
+
+/*
+#cgo LDFLAGS: -framework CoreFoundation -framework IOKit
+
+#include <stdlib.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/pwr_mgt/IOPMLib.h>
+
+#include "cfhelpers.h"
+
+// C-side struct for a single power assertion.
+typedef struct {
+    int  pid;
+    char process_name[256];
+    char type[256];
+    char name[256];
+    long level;
+} c_assertion;
+
+// Copies every active power assertion into out, up to max_count entries.
+// Returns the number of entries written, or -1 if the query failed.
+static int copy_assertions(c_assertion *out, int max_count) {
+    CFDictionaryRef by_process = NULL;
+    if (IOPMCopyAssertionsByProcess(&by_process) != kIOReturnSuccess || by_process == NULL) {
+        return -1;
+    }
+
+    // The dictionary maps a CFNumber pid to a CFArray of assertion dictionaries.
+    CFIndex process_count = CFDictionaryGetCount(by_process);
+    const void **pids = malloc(sizeof(void *) * process_count);
+    const void **lists = malloc(sizeof(void *) * process_count);
+    if (pids == NULL || lists == NULL) {
+        free(pids);
+        free(lists);
+        CFRelease(by_process);
+        return -1;
+    }
+    CFDictionaryGetKeysAndValues(by_process, pids, lists);
+
+    int count = 0;
+    for (CFIndex i = 0; i < process_count && count < max_count; i++) {
+        int pid = 0;
+        if (pids[i] != NULL && CFGetTypeID(pids[i]) == CFNumberGetTypeID()) {
+            CFNumberGetValue((CFNumberRef)pids[i], kCFNumberIntType, &pid);
+        }
+        if (lists[i] == NULL || CFGetTypeID(lists[i]) != CFArrayGetTypeID()) continue;
+
+        CFArrayRef list = (CFArrayRef)lists[i];
+        CFIndex list_count = CFArrayGetCount(list);
+        for (CFIndex j = 0; j < list_count && count < max_count; j++) {
+            CFDictionaryRef assertion = (CFDictionaryRef)CFArrayGetValueAtIndex(list, j);
+            if (assertion == NULL || CFGetTypeID(assertion) != CFDictionaryGetTypeID()) continue;
+
+            c_assertion *a = &out[count++];
+            a->pid = pid;
+            get_string_prop(assertion, "Process Name", a->process_name, 256);
+            get_string_prop(assertion, "AssertType", a->type, 256);
+            get_string_prop(assertion, "AssertName", a->name, 256);
+            a->level = get_long_prop(assertion, "AssertLevel");
+        }
+    }
+
+    free(pids);
+    free(lists);
+    CFRelease(by_process);
+    return count;
+}
+*/
+import "C"
+import "errors"
+
+// maxAssertions bounds how many assertions ActiveAssertions reports.
+const maxAssertions = 512
+
+// copyActiveAssertions queries IOPMCopyAssertionsByProcess.
+func copyActiveAssertions() ([]Assertion, error) {
+	buf := make([]C.c_assertion, maxAssertions)
+	n := C.copy_assertions(&buf[0], C.int(len(buf)))
+	if n < 0 {
+		return nil, errors.New("IOPMCopyAssertionsByProcess failed")
+	}
+
+	assertions := make([]Assertion, int(n))
+	for i := range assertions {
+		a := &buf[i]
+		assertions[i] = Assertion{
+			PID:     int(a.pid),
+			Process: C.GoString(&a.process_name[0]),
+			Type:    C.GoString(&a._type[0]),
+			Name:    C.GoString(&a.name[0]),
+			Level:   int(a.level),
+		}
+	}
+	return assertions, nil
+}
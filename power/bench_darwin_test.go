@@ -0,0 +1,35 @@
+//go:build darwin && cgo
+
+package power
+
+import (
+	"errors"
+	"testing"
+)
+
+// The read benchmarks compare the allocations of a plain read with those of
+// a Session read, which skips the identity strings between refreshes:
+//
+//	go test -run '^$' -bench . ./power
+
+func BenchmarkGetBatteryInfo(b *testing.B) {
+	benchmarkRead(b, GetBatteryInfo)
+}
+
+func BenchmarkSession(b *testing.B) {
+	benchmarkRead(b, NewSession(Options{}, 0).GetBatteryInfo)
+}
+
+func benchmarkRead(b *testing.B, read func() (*BatteryInfo, error)) {
+	if _, err := read(); errors.Is(err, ErrServiceVanished) {
+		b.Skip("no AppleSmartBattery service on this machine")
+	} else if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := read(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package power
+
+import "time"
+
+// budgetStages are the sections GetBatteryInfoBudget decodes, most important
+// first: charge and live power, then the adapter, then the nested battery
+// details (cell voltages, gauge status, lifetime data), identity strings and
+// charger setpoints. The last stage decodes the state again with the nested
+// dictionaries, since some machines report OptimizedBatteryChargingEngaged
+// only inside ChargerData.
+var budgetStages = []readSections{
+	sectionState | sectionBattery,
+	sectionAdapter | sectionNested,
+	sectionState | sectionBattery | sectionCharger | sectionNested | sectionIdentity,
+}
+
+// GetBatteryInfoBudget is like GetBatteryInfo, but stops decoding once
+// budget has elapsed and returns whatever it has so far. The properties are
+// copied from the registry once, so every field comes from the same instant,
+// and the copy is then decoded in stages in priority order: state, charge
+// and pack power first (without any nested dictionaries), then adapter
+// details, then cell voltages, gauge and lifetime data, the identity strings
+// and the charger setpoints. complete is false if the budget ran out before
+// the last stage, in which case the fields of the skipped stages are zero
+// and their capabilities absent. Calculations are derived from whatever was
+// decoded. ReadLatency covers only the copy from the registry, as it does for
+// GetBatteryInfo, not the decoding.
+//
+// The copy and a single stage can't be interrupted, so the budget is checked
+// between stages and the first stage always runs: the call can overrun the
+// budget by the copy and one stage.
+func GetBatteryInfoBudget(budget time.Duration) (info *BatteryInfo, complete bool, err error) {
+	info, complete, err = readBatteryInfoStaged(budgetStages, time.Now().Add(budget))
+	if err != nil {
+		return nil, false, err
+	}
+	calculateDerivedMetrics(info, Options{})
+	return info, complete, nil
+}
@@ -0,0 +1,42 @@
+//go:build darwin && cgo
+
+package power
+
+import "testing"
+
+// chargerDataOptimizedPlist has OptimizedBatteryChargingEngaged only inside
+// ChargerData, as some machines report it.
+const chargerDataOptimizedPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>ExternalConnected</key><true/>
+	<key>IsCharging</key><false/>
+	<key>FullyCharged</key><false/>
+	<key>AppleRawCurrentCapacity</key><integer>4780</integer>
+	<key>AppleRawMaxCapacity</key><integer>5832</integer>
+	<key>DesignCapacity</key><integer>6249</integer>
+	<key>Voltage</key><integer>12844</integer>
+	<key>Amperage</key><integer>0</integer>
+	<key>ChargerData</key>
+	<dict>
+		<key>ChargingVoltage</key><integer>13050</integer>
+		<key>NotChargingReason</key><integer>4</integer>
+		<key>OptimizedBatteryChargingEngaged</key><true/>
+	</dict>
+</dict>
+</plist>
+`
+
+func TestBudgetStagesReadNestedOptimizedCharging(t *testing.T) {
+	info, err := decodeStagedPlist([]byte(chargerDataOptimizedPlist), budgetStages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Capabilities[CapOptimizedCharging] {
+		t.Errorf("CapOptimizedCharging = false, want true")
+	}
+	if !info.State.OptimizedChargingActive {
+		t.Errorf("OptimizedChargingActive = false, want true")
+	}
+}
@@ -0,0 +1,259 @@
+package power
+
+import "math"
+
+// Recalculate recomputes the Calculations of a snapshot from its raw State,
+// Battery and Adapter fields, exactly as GetBatteryInfoWithOptions derives
+// them after an IOKit read. Use it on snapshots that were decoded from JSON,
+// edited, or built by hand; their Amperage is expected in Apple's convention
+// (positive when charging).
+//
+// A snapshot read with Options.AmperagePositiveWhenDischarging remembers
+// that its Amperage is inverted only until it is serialized: the convention
+// isn't part of the JSON, so after decoding such a snapshot Recalculate
+// treats the inverted Amperage as Apple's and reports charging as
+// discharging and vice versa. Marshal snapshots read with the zero Options,
+// or negate Battery.Amperage after decoding, before calling Recalculate.
+func (b *BatteryInfo) Recalculate(opts Options) {
+	b.Battery.Amperage = b.appleAmperage()
+	b.Calculations = Calculations{}
+	calculateDerivedMetrics(b, opts)
+}
+
+// calculateDerivedMetrics populates the Calculations struct with health
+// percentages and live power flow data in Watts.
+func calculateDerivedMetrics(info *BatteryInfo, opts Options) {
+	normalizeMaxCapacity(&info.Battery)
+
+	// --- Charge Power Envelope ---
+	info.Battery.MaxChargePower = 0
+	hasChargePower := info.Has(CapMaxChargeCurrent) && info.Has(CapChargerData) && info.Charger.ChargingVoltage > 0
+	if hasChargePower {
+		info.Battery.MaxChargePower = finite(info.Battery.MaxChargeCurrent * info.Charger.ChargingVoltage)
+	}
+	if info.Capabilities != nil {
+		info.Capabilities[CapMaxChargePower] = hasChargePower
+	}
+
+	// --- Calibration Hold ---
+	info.State.CalibrationInProgress = info.State.IsConnected && !info.State.IsCharging &&
+		!info.State.FullyCharged && !info.State.OptimizedChargingActive &&
+		info.Battery.CalibrationNeeded
+
+	// --- Health Percentage Calculations ---
+	if info.Battery.DesignCapacity > 0 {
+		designCapF := float64(info.Battery.DesignCapacity)
+
+		healthByMax := (float64(info.Battery.MaxCapacity) / designCapF) * 100.0
+		info.Calculations.HealthByMaxCapacity = int(math.Round(healthByMax))
+
+		healthByNominal := (float64(info.Battery.NominalCapacity) / designCapF) * 100.0
+		info.Calculations.HealthByNominalCapacity = int(math.Round(healthByNominal))
+
+		info.Calculations.HealthByMaxCapacityFloat = finite(healthByMax)
+		info.Calculations.HealthByNominalCapacityFloat = finite(healthByNominal)
+
+		var conditionModifier float64
+		if len(info.Battery.IndividualCellVoltages) > 1 {
+			minV, maxV := findMinMax(info.Battery.IndividualCellVoltages)
+			drift := maxV - minV
+			switch {
+			case drift <= 5:
+				conditionModifier = 2.5
+			case drift <= 15:
+				conditionModifier = 1.0
+			case drift <= 30:
+				conditionModifier = 0.0
+			case drift <= 50:
+				conditionModifier = -2.0
+			default:
+				conditionModifier = -10.0
+			}
+		}
+		info.Calculations.ConditionAdjustedHealth = int(math.Round(healthByNominal + conditionModifier))
+
+		if opts.ClampHealth {
+			info.Calculations.HealthByMaxCapacity = min(info.Calculations.HealthByMaxCapacity, 100)
+			info.Calculations.HealthByNominalCapacity = min(info.Calculations.HealthByNominalCapacity, 100)
+			info.Calculations.ConditionAdjustedHealth = min(info.Calculations.ConditionAdjustedHealth, 100)
+			info.Calculations.HealthByMaxCapacityFloat = min(info.Calculations.HealthByMaxCapacityFloat, 100)
+			info.Calculations.HealthByNominalCapacityFloat = min(info.Calculations.HealthByNominalCapacityFloat, 100)
+		}
+	}
+
+	// --- Remaining Rated Cycles ---
+	info.Calculations.CyclesRemaining = -1
+	if rated, ok := DesignCycleCount(info.Battery.DeviceName); ok {
+		info.Calculations.CyclesRemaining = max(rated-info.Battery.CycleCount, 0)
+	}
+
+	// --- Power Flow Calculations (Watts = Volts * Amps) ---
+
+	// Helper function to truncate a float64 to two decimal places without
+	// rounding, or to round it to Options.DecimalPlaces when that is set.
+	// Non-finite readings (e.g., from a hand-built snapshot) become 0 so
+	// they can't leak into JSON.
+	truncate := func(f float64) float64 {
+		return math.Trunc(finite(f)*100) / 100
+	}
+	if opts.DecimalPlaces > 0 {
+		truncate = func(f float64) float64 {
+			return roundTo(f, opts.DecimalPlaces)
+		}
+	}
+
+	// Power being drawn from the AC adapter.
+	acPower := info.Adapter.InputVoltage * info.Adapter.InputAmperage
+	info.Calculations.ACPower = truncate(acPower)
+	info.Calculations.adapterWatts = info.Adapter.EffectiveWatts()
+
+	// Power flowing into (+) or out of (-) the battery.
+	batteryPower := info.Battery.Voltage * info.Battery.Amperage
+	info.Calculations.BatteryPower = truncate(batteryPower)
+
+	// The power consumed by the system (CPU, screen, etc.) is the combination of
+	// power from the AC adapter and power from the battery.
+	// If the battery is discharging, its power contribution is negative.
+	// On battery with no adapter attached, ACPower is 0 and this reduces to
+	// -BatteryPower, i.e. the (positive) discharge rate.
+	systemPower := info.Calculations.ACPower - info.Calculations.BatteryPower
+	info.Calculations.SystemPower = truncate(systemPower)
+	info.Calculations.SystemPowerAvailable = true
+
+	// Some machines don't report PowerTelemetryData. While plugged in that
+	// leaves ACPower at 0 and the identity above meaningless, so report the
+	// system power as unavailable rather than a misleading figure.
+	if info.State.IsConnected && info.Adapter.InputVoltage == 0 && info.Adapter.InputAmperage == 0 {
+		info.Calculations.SystemPower = 0
+		info.Calculations.SystemPowerAvailable = false
+	}
+
+	// Everything above uses Apple's convention. Consumers that prefer
+	// discharge to be positive get Amperage and BatteryPower negated at the
+	// end; SystemPower is the same physical quantity either way, so the
+	// identity becomes SystemPower = ACPower + BatteryPower.
+	if opts.AmperagePositiveWhenDischarging {
+		info.Battery.Amperage = -info.Battery.Amperage
+		info.Calculations.BatteryPower = -info.Calculations.BatteryPower
+		info.Calculations.amperageInverted = true
+	}
+
+	if opts.DecimalPlaces > 0 {
+		roundReadings(info, opts.DecimalPlaces)
+	}
+}
+
+// roundReadings rounds the voltage, current and temperature readings of a
+// snapshot to the given number of decimal places.
+func roundReadings(info *BatteryInfo, places int) {
+	for _, f := range []*float64{
+		&info.Battery.Temperature,
+		&info.Battery.Voltage,
+		&info.Battery.Amperage,
+		&info.Battery.MaxChargeCurrent,
+		&info.Battery.MaxDischargeCurrent,
+		&info.Battery.MaxChargePower,
+		&info.Battery.BatteryDataVoltage,
+		&info.Adapter.MaxVoltage,
+		&info.Adapter.MaxAmperage,
+		&info.Adapter.CableCurrentRating,
+		&info.Adapter.Temperature,
+		&info.Adapter.InputVoltage,
+		&info.Adapter.InputAmperage,
+		&info.Charger.ChargingVoltage,
+		&info.Charger.ChargingCurrent,
+		&info.Charger.VacVoltageLimit,
+	} {
+		*f = roundTo(*f, places)
+	}
+}
+
+// maxDecimalPlaces is the most decimal places roundTo honours; a float64
+// carries no more significant digits than this, and larger scales overflow.
+const maxDecimalPlaces = 15
+
+// roundTo rounds f to the given number of decimal places. Non-finite values
+// become 0.
+func roundTo(f float64, places int) float64 {
+	f = finite(f)
+	if places > maxDecimalPlaces {
+		return f
+	}
+	scale := math.Pow(10, float64(places))
+	return math.Round(f*scale) / scale
+}
+
+// finite returns f, or 0 if f is NaN or infinite. The derived helpers use it
+// so that a zero or corrupt input yields 0 rather than a value that
+// encoding/json refuses to marshal.
+func finite(f float64) float64 {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+	return f
+}
+
+// appleAmperage returns Battery.Amperage in Apple's convention (positive when
+// charging), regardless of Options.AmperagePositiveWhenDischarging.
+func (b *BatteryInfo) appleAmperage() float64 {
+	if b.Calculations.amperageInverted {
+		return -b.Battery.Amperage
+	}
+	return b.Battery.Amperage
+}
+
+// appleBatteryPower returns BatteryPower in Apple's convention (positive when
+// charging), regardless of Options.AmperagePositiveWhenDischarging.
+func (c Calculations) appleBatteryPower() float64 {
+	if c.amperageInverted {
+		return -c.BatteryPower
+	}
+	return c.BatteryPower
+}
+
+// BatteryFlow returns the power flowing through the battery as a magnitude in
+// Watts, with charging reporting its direction: true when power is going into
+// the battery, false when it is coming out or nothing is flowing. Unlike the
+// signed BatteryPower, whose sign depends on
+// Options.AmperagePositiveWhenDischarging, the result is the same whichever
+// convention the snapshot was read with, as long as the Calculations were
+// derived in this process. Decoded from JSON, Calculations of a snapshot read
+// with AmperagePositiveWhenDischarging report the opposite direction, since
+// the convention isn't serialized; see Recalculate.
+func (c Calculations) BatteryFlow() (watts float64, charging bool) {
+	power := c.appleBatteryPower()
+	return math.Abs(power), power > 0
+}
+
+// Helper to find min/max in a slice
+func findMinMax(a []int) (min int, max int) {
+	if len(a) == 0 {
+		return 0, 0
+	}
+	min = a[0]
+	max = a[0]
+	for _, value := range a {
+		if value < min {
+			min = value
+		}
+		if value > max {
+			max = value
+		}
+	}
+	return min, max
+}
+
+// SystemDraw returns the power being consumed by the system in Watts, as a
+// non-negative number regardless of whether it comes from the adapter, the
+// battery, or both.
+//
+// It is SystemPower with sensor noise below zero clamped away: on AC power it
+// is the adapter input minus whatever goes into the battery, and on battery
+// alone (ACPower == 0) it is the battery's discharge rate. It is 0 when
+// SystemPowerAvailable is false.
+func (c Calculations) SystemDraw() float64 {
+	if c.SystemPower < 0 {
+		return 0
+	}
+	return c.SystemPower
+}
@@ -0,0 +1,262 @@
+package power
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// degenerateSnapshots are snapshots with missing or non-finite readings,
+// such as a hand-built or partially decoded snapshot can hold.
+func degenerateSnapshots() map[string]*BatteryInfo {
+	nan, inf := math.NaN(), math.Inf(1)
+	base := func(edit func(*BatteryInfo)) *BatteryInfo {
+		info := &BatteryInfo{
+			State: State{IsCharging: true, IsConnected: true},
+			Battery: Battery{
+				DesignCapacity:         6075,
+				MaxCapacity:            5612,
+				NominalCapacity:        5720,
+				CurrentCapacity:        3871,
+				Voltage:                12.437,
+				Amperage:               2.104,
+				MaxChargeCurrent:       4.312,
+				IndividualCellVoltages: []int{4144, 4147, 4146},
+			},
+			Adapter: Adapter{MaxWatts: 96, InputVoltage: 19.82, InputAmperage: 2.14},
+			Charger: Charger{ChargingVoltage: 13.05},
+			Capabilities: map[Capability]bool{
+				CapMaxChargeCurrent: true,
+				CapChargerData:      true,
+			},
+		}
+		edit(info)
+		return info
+	}
+	return map[string]*BatteryInfo{
+		"zero value":       {},
+		"zero design":      base(func(b *BatteryInfo) { b.Battery.DesignCapacity = 0 }),
+		"zero max":         base(func(b *BatteryInfo) { b.Battery.MaxCapacity = 0 }),
+		"zero current":     base(func(b *BatteryInfo) { b.Battery.CurrentCapacity = 0 }),
+		"zero capacities":  base(func(b *BatteryInfo) { b.Battery = Battery{Voltage: 12.437, Amperage: 2.104} }),
+		"zero voltage":     base(func(b *BatteryInfo) { b.Battery.Voltage = 0 }),
+		"no cells":         base(func(b *BatteryInfo) { b.Battery.IndividualCellVoltages = nil }),
+		"trickle charge":   base(func(b *BatteryInfo) { b.Battery.Amperage = 1e-300 }),
+		"discharging":      base(func(b *BatteryInfo) { b.Battery.Amperage = -1.5; b.State.IsCharging = false }),
+		"NaN voltage":      base(func(b *BatteryInfo) { b.Battery.Voltage = nan }),
+		"NaN amperage":     base(func(b *BatteryInfo) { b.Battery.Amperage = nan }),
+		"+Inf amperage":    base(func(b *BatteryInfo) { b.Battery.Amperage = inf }),
+		"-Inf amperage":    base(func(b *BatteryInfo) { b.Battery.Amperage = -inf }),
+		"+Inf voltage":     base(func(b *BatteryInfo) { b.Battery.Voltage = inf }),
+		"NaN input power":  base(func(b *BatteryInfo) { b.Adapter.InputVoltage = nan; b.Adapter.InputAmperage = nan }),
+		"Inf input power":  base(func(b *BatteryInfo) { b.Adapter.InputAmperage = inf }),
+		"Inf charge limit": base(func(b *BatteryInfo) { b.Battery.MaxChargeCurrent = inf }),
+		"NaN temperature":  base(func(b *BatteryInfo) { b.Battery.Temperature = nan }),
+	}
+}
+
+// checkFinite fails t if v is NaN or infinite.
+func checkFinite(t *testing.T, name string, v float64) {
+	t.Helper()
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		t.Errorf("%s = %v, want a finite value", name, v)
+	}
+}
+
+func TestRecalculateDegenerate(t *testing.T) {
+	for name, info := range degenerateSnapshots() {
+		for _, opts := range []Options{{}, {ClampHealth: true, DecimalPlaces: 1, AmperagePositiveWhenDischarging: true}} {
+			t.Run(name, func(t *testing.T) {
+				info.Recalculate(opts)
+				c := info.Calculations
+				checkFinite(t, "HealthByMaxCapacityFloat", c.HealthByMaxCapacityFloat)
+				checkFinite(t, "HealthByNominalCapacityFloat", c.HealthByNominalCapacityFloat)
+				checkFinite(t, "ACPower", c.ACPower)
+				checkFinite(t, "BatteryPower", c.BatteryPower)
+				checkFinite(t, "SystemPower", c.SystemPower)
+				checkFinite(t, "Battery.MaxChargePower", info.Battery.MaxChargePower)
+				if info.Battery.DesignCapacity == 0 && (c.HealthByMaxCapacity != 0 || c.HealthByNominalCapacity != 0) {
+					t.Errorf("health = %d/%d with no design capacity, want 0", c.HealthByMaxCapacity, c.HealthByNominalCapacity)
+				}
+			})
+		}
+	}
+}
+
+func TestDerivedHelpersDegenerate(t *testing.T) {
+	reference := degenerateSnapshots()["zero current"]
+	for name, info := range degenerateSnapshots() {
+		t.Run(name, func(t *testing.T) {
+			calculateDerivedMetrics(info, Options{})
+
+			fraction := info.ChargeFraction()
+			checkFinite(t, "ChargeFraction", fraction)
+			if info.Battery.MaxCapacity <= 0 && fraction != 0 {
+				t.Errorf("ChargeFraction = %v with no max capacity, want 0", fraction)
+			}
+
+			rate := info.DischargeCRate()
+			checkFinite(t, "DischargeCRate", rate)
+			if rate < 0 {
+				t.Errorf("DischargeCRate = %v, want >= 0", rate)
+			}
+
+			if eta, ok := info.TimeToChargePercent(80); eta < 0 || (!ok && eta != 0) {
+				t.Errorf("TimeToChargePercent(80) = %v, %v", eta, ok)
+			}
+
+			checkFinite(t, "EnergyWh", info.EnergyWh())
+			checkFinite(t, "DesignEnergyWh", info.DesignEnergyWh())
+			checkFinite(t, "EnergyDelta", EnergyDelta(reference, info))
+			checkFinite(t, "EnergyDelta", EnergyDelta(info, info))
+
+			_ = info.ToSample(time.Unix(0, 0))
+		})
+	}
+}
+
+func TestDerivedHelpersZeroInputs(t *testing.T) {
+	tests := []struct {
+		name  string
+		info  BatteryInfo
+		got   func(*BatteryInfo) float64
+		want  float64
+		field string
+	}{
+		{"ChargeFraction zero max", BatteryInfo{Battery: Battery{CurrentCapacity: 100}}, (*BatteryInfo).ChargeFraction, 0, "ChargeFraction"},
+		{"ChargeFraction half", BatteryInfo{Battery: Battery{MaxCapacity: 200, CurrentCapacity: 100}}, (*BatteryInfo).ChargeFraction, 0.5, "ChargeFraction"},
+		{"DischargeCRate zero max", BatteryInfo{Battery: Battery{Amperage: -2}}, (*BatteryInfo).DischargeCRate, 0, "DischargeCRate"},
+		{"DischargeCRate charging", BatteryInfo{Battery: Battery{MaxCapacity: 5000, Amperage: 2}}, (*BatteryInfo).DischargeCRate, 0, "DischargeCRate"},
+		{"DischargeCRate 0.5C", BatteryInfo{Battery: Battery{MaxCapacity: 4000, Amperage: -2}}, (*BatteryInfo).DischargeCRate, 0.5, "DischargeCRate"},
+		{"EnergyWh zero voltage", BatteryInfo{Battery: Battery{CurrentCapacity: 4000}}, (*BatteryInfo).EnergyWh, 0, "EnergyWh"},
+		{"EnergyWh zero capacity", BatteryInfo{Battery: Battery{Voltage: 12}}, (*BatteryInfo).EnergyWh, 0, "EnergyWh"},
+		{"EnergyWh", BatteryInfo{Battery: Battery{CurrentCapacity: 4000, Voltage: 12}}, (*BatteryInfo).EnergyWh, 48, "EnergyWh"},
+		{"DesignEnergyWh no cells", BatteryInfo{Battery: Battery{DesignCapacity: 5000}}, (*BatteryInfo).DesignEnergyWh, 0, "DesignEnergyWh"},
+		{"DesignEnergyWh zero design", BatteryInfo{Battery: Battery{IndividualCellVoltages: []int{4000, 4000, 4000}}}, (*BatteryInfo).DesignEnergyWh, 0, "DesignEnergyWh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got(&tt.info); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("%s = %v, want %v", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeToChargePercentZeroInputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		battery Battery
+		target  int
+		ok      bool
+	}{
+		{"zero max", Battery{CurrentCapacity: 1000, Amperage: 2}, 80, false},
+		{"not charging", Battery{MaxCapacity: 5000, CurrentCapacity: 1000}, 80, false},
+		{"past target", Battery{MaxCapacity: 5000, CurrentCapacity: 4500, Amperage: 2}, 80, false},
+		{"target out of range", Battery{MaxCapacity: 5000, CurrentCapacity: 1000, Amperage: 2}, 101, false},
+		{"trickle", Battery{MaxCapacity: 5000, CurrentCapacity: 1000, Amperage: 1e-300}, 80, false},
+		{"NaN amperage", Battery{MaxCapacity: 5000, CurrentCapacity: 1000, Amperage: math.NaN()}, 80, false},
+		{"Inf amperage", Battery{MaxCapacity: 5000, CurrentCapacity: 1000, Amperage: math.Inf(1)}, 80, false},
+		{"charging", Battery{MaxCapacity: 5000, CurrentCapacity: 1000, Amperage: 3}, 80, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := BatteryInfo{Battery: tt.battery}
+			eta, ok := info.TimeToChargePercent(tt.target)
+			if ok != tt.ok || eta < 0 || (!ok && eta != 0) {
+				t.Errorf("TimeToChargePercent(%d) = %v, %v, want ok %v", tt.target, eta, ok, tt.ok)
+			}
+			if tt.name == "charging" && eta != time.Hour {
+				t.Errorf("TimeToChargePercent(%d) = %v, want 1h", tt.target, eta)
+			}
+		})
+	}
+}
+
+func TestEnergyDeltaZeroInputs(t *testing.T) {
+	cells := []int{4000, 4000, 4000}
+	a := &BatteryInfo{Battery: Battery{CurrentCapacity: 1000, IndividualCellVoltages: cells}}
+	b := &BatteryInfo{Battery: Battery{CurrentCapacity: 2000}}
+	tests := []struct {
+		name string
+		a, b *BatteryInfo
+		want float64
+	}{
+		{"nil a", nil, b, 0},
+		{"nil b", a, nil, 0},
+		{"no cell counts", b, b, 0},
+		{"cell count from a", a, b, 11.4},
+		{"cell count from b", b, a, -11.4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EnergyDelta(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("EnergyDelta = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToSampleClamps(t *testing.T) {
+	info := &BatteryInfo{
+		Battery: Battery{
+			MaxCapacity:             100,
+			CurrentCapacity:         1000,
+			TemperatureCentidegrees: 1 << 20,
+			VoltageMV:               -5,
+			AmperageMA:              -1 << 20,
+			CycleCount:              -1,
+		},
+		Calculations: Calculations{
+			ACPower:      math.Inf(1),
+			BatteryPower: math.NaN(),
+			SystemPower:  -1e12,
+		},
+	}
+	got := info.ToSample(time.UnixMilli(1234))
+	want := Sample{
+		UnixMilli:    1234,
+		ChargePct:    math.MaxUint8,
+		TempCentideg: math.MaxInt16,
+		VoltageMV:    0,
+		AmperageMA:   math.MinInt16,
+		BatteryMW:    0,
+		SystemMW:     math.MinInt32,
+		ACMW:         0,
+		CycleCount:   0,
+	}
+	if got != want {
+		t.Errorf("ToSample =\n %+v, want\n %+v", got, want)
+	}
+}
+
+func TestAmperagePositiveWhenDischarging(t *testing.T) {
+	for _, amperage := range []float64{2.104, -1.5, 0} {
+		info := &BatteryInfo{
+			State:   State{IsConnected: true},
+			Battery: Battery{Voltage: 12.5, Amperage: amperage},
+			Adapter: Adapter{InputVoltage: 20, InputAmperage: 2},
+		}
+		info.Recalculate(Options{})
+		apple := info.Calculations
+
+		info.Recalculate(Options{AmperagePositiveWhenDischarging: true})
+		flipped := info.Calculations
+		if info.Battery.Amperage != -amperage || flipped.BatteryPower != -apple.BatteryPower {
+			t.Errorf("amperage %v: got Amperage %v and BatteryPower %v, want them negated from %v and %v",
+				amperage, info.Battery.Amperage, flipped.BatteryPower, amperage, apple.BatteryPower)
+		}
+		if flipped.SystemPower != apple.SystemPower {
+			t.Errorf("amperage %v: SystemPower = %v, want %v either way", amperage, flipped.SystemPower, apple.SystemPower)
+		}
+		if got := flipped.ACPower + flipped.BatteryPower; math.Abs(got-flipped.SystemPower) > 0.011 {
+			t.Errorf("amperage %v: ACPower + BatteryPower = %v, want SystemPower %v", amperage, got, flipped.SystemPower)
+		}
+
+		// Recalculating restores Apple's convention.
+		info.Recalculate(Options{})
+		if info.Battery.Amperage != amperage {
+			t.Errorf("amperage %v: Amperage = %v after Recalculate, want it restored", amperage, info.Battery.Amperage)
+		}
+	}
+}
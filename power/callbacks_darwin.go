@@ -0,0 +1,16 @@
+package power
+
+// This file only holds functions exported to C. cgo forbids C definitions in
+// the preamble of a file that uses //export, so the C side of each callback
+// lives with the code that registers it.
+
+/*
+#include <stdint.h>
+*/
+import "C"
+import "runtime/cgo"
+
+//export goPowerSourceChanged
+func goPowerSourceChanged(handle C.uintptr_t) {
+	cgo.Handle(handle).Value().(func())()
+}
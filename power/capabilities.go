@@ -0,0 +1,66 @@
+package power
+
+// Capability names an optional data point that only some machines, batteries,
+// or adapters report. Fields backed by a capability read as zero when it is
+// absent, so consult BatteryInfo.Capabilities before trusting a zero.
+type Capability string
+
+const (
+	// CapAdapterCableCurrentRating reports whether Adapter.CableCurrentRating
+	// was provided by the adapter.
+	CapAdapterCableCurrentRating Capability = "AdapterCableCurrentRating"
+
+	// CapAdapterTemperature reports whether Adapter.Temperature was provided
+	// by the adapter.
+	CapAdapterTemperature Capability = "AdapterTemperature"
+
+	// CapAdapterIsWireless reports whether Adapter.IsWireless was provided
+	// by the adapter.
+	CapAdapterIsWireless Capability = "AdapterIsWireless"
+
+	// CapAdapterPDRevision reports whether Adapter.PDRevision was provided
+	// by the adapter.
+	CapAdapterPDRevision Capability = "AdapterPDRevision"
+
+	// CapAdapterPPS reports whether Adapter.SupportsPPS was provided by the
+	// adapter.
+	CapAdapterPPS Capability = "AdapterPPS"
+
+	// CapOptimizedCharging reports whether the system exposes the Optimized
+	// Battery Charging state behind State.OptimizedChargingActive.
+	CapOptimizedCharging Capability = "OptimizedCharging"
+
+	// CapGaugeStatus reports whether the gauge exposes the status word behind
+	// Battery.GaugeStatus and Battery.CalibrationNeeded.
+	CapGaugeStatus Capability = "GaugeStatus"
+
+	// CapChargerData reports whether the system exposes the ChargerData
+	// dictionary behind BatteryInfo.Charger.
+	CapChargerData Capability = "ChargerData"
+
+	// CapMaxChargeCurrent reports whether the gauge recorded the lifetime
+	// maximum behind Battery.MaxChargeCurrent.
+	CapMaxChargeCurrent Capability = "MaxChargeCurrent"
+
+	// CapMaxDischargeCurrent reports whether the gauge recorded the
+	// lifetime maximum behind Battery.MaxDischargeCurrent.
+	CapMaxDischargeCurrent Capability = "MaxDischargeCurrent"
+
+	// CapMaxChargePower reports whether both inputs of
+	// Battery.MaxChargePower were reported: the lifetime maximum charge
+	// current and ChargerData's charge voltage.
+	CapMaxChargePower Capability = "MaxChargePower"
+
+	// CapTimeSinceFullCharge reports whether the gauge tracks the usage
+	// statistic behind Battery.TimeSinceFullCharge. It is model-specific.
+	CapTimeSinceFullCharge Capability = "TimeSinceFullCharge"
+
+	// CapBatteryDataVoltage reports whether the gauge repeats the pack
+	// voltage inside BatteryData, behind Battery.BatteryDataVoltage.
+	CapBatteryDataVoltage Capability = "BatteryDataVoltage"
+)
+
+// Has reports whether the snapshot includes the given optional data point.
+func (b *BatteryInfo) Has(c Capability) bool {
+	return b != nil && b.Capabilities[c]
+}
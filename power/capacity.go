@@ -0,0 +1,27 @@
+package power
+
+import "math"
+
+const (
+	// percentCapacityMax is the largest MaxCapacity that can be a
+	// percentage rather than mAh.
+	percentCapacityMax = 100
+
+	// percentCapacityMinDesign is the smallest DesignCapacity, in mAh, for
+	// which a MaxCapacity of at most percentCapacityMax is taken to be a
+	// percentage. Every notebook pack is well above it, and a real pack of
+	// that size never has a full-charge capacity as low as 100 mAh.
+	percentCapacityMinDesign = 1000
+)
+
+// normalizeMaxCapacity converts a MaxCapacity reported as a percentage of
+// DesignCapacity to mAh, and records that it did so in
+// MaxCapacityFromPercent. A MaxCapacity already in mAh is left alone, so it
+// is safe to apply more than once.
+func normalizeMaxCapacity(b *Battery) {
+	if b.MaxCapacity <= 0 || b.MaxCapacity > percentCapacityMax || b.DesignCapacity < percentCapacityMinDesign {
+		return
+	}
+	b.MaxCapacity = int(math.Round(float64(b.MaxCapacity) * float64(b.DesignCapacity) / 100.0))
+	b.MaxCapacityFromPercent = true
+}
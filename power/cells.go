@@ -0,0 +1,87 @@
+package power
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cellLabelPrefix starts every CellVoltages label.
+const cellLabelPrefix = "Cell"
+
+// CellVoltages maps 1-based cell labels, e.g. "Cell1", to cell voltages in
+// mV. Labels and the JSON encoding list the cells by index, so that on packs
+// with ten or more cell blocks "Cell10" follows "Cell9" rather than "Cell1".
+type CellVoltages map[string]int
+
+// CellVoltageMap returns the individual cell voltages (in mV) keyed by a
+// 1-based label, e.g. {"Cell1": 4123, "Cell2": 4125, "Cell3": 4121}, numbered
+// in the order IOKit reports them in. It returns nil when no cell voltages
+// were reported.
+func (b *BatteryInfo) CellVoltageMap() CellVoltages {
+	if len(b.Battery.IndividualCellVoltages) == 0 {
+		return nil
+	}
+	m := make(CellVoltages, len(b.Battery.IndividualCellVoltages))
+	for i, mv := range b.Battery.IndividualCellVoltages {
+		m[cellLabelPrefix+strconv.Itoa(i+1)] = mv
+	}
+	return m
+}
+
+// Labels returns the labels of m in cell order. Labels that aren't of the
+// form "CellN" sort after the others, by string.
+func (m CellVoltages) Labels() []string {
+	labels := make([]string, 0, len(m))
+	for label := range m {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		a, aok := cellIndex(labels[i])
+		b, bok := cellIndex(labels[j])
+		switch {
+		case aok && bok && a != b:
+			return a < b
+		case aok != bok:
+			return aok
+		}
+		return labels[i] < labels[j]
+	})
+	return labels
+}
+
+// MarshalJSON encodes m as a JSON object with its keys in Labels order
+// rather than encoding/json's string order.
+func (m CellVoltages) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, label := range m.Labels() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(label)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.WriteString(strconv.Itoa(m[label]))
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// cellIndex returns N for a label of the form "CellN".
+func cellIndex(label string) (int, bool) {
+	digits, ok := strings.CutPrefix(label, cellLabelPrefix)
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(digits)
+	return n, err == nil
+}
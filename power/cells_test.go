@@ -0,0 +1,40 @@
+package power
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCellVoltagesOrder(t *testing.T) {
+	info := &BatteryInfo{Battery: Battery{IndividualCellVoltages: []int{
+		4101, 4102, 4103, 4104, 4105, 4106, 4107, 4108, 4109, 4110, 4111,
+	}}}
+	m := info.CellVoltageMap()
+
+	wantLabels := []string{
+		"Cell1", "Cell2", "Cell3", "Cell4", "Cell5", "Cell6",
+		"Cell7", "Cell8", "Cell9", "Cell10", "Cell11",
+	}
+	if got := m.Labels(); !reflect.DeepEqual(got, wantLabels) {
+		t.Errorf("Labels() = %v, want %v", got, wantLabels)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Cell1":4101,"Cell2":4102,"Cell3":4103,"Cell4":4104,"Cell5":4105,"Cell6":4106,` +
+		`"Cell7":4107,"Cell8":4108,"Cell9":4109,"Cell10":4110,"Cell11":4111}`
+	if string(data) != want {
+		t.Errorf("json.Marshal = %s, want %s", data, want)
+	}
+}
+
+func TestCellVoltagesLabelsMixed(t *testing.T) {
+	m := CellVoltages{"Cell10": 1, "Cell2": 2, "Pack": 3, "CellX": 4}
+	want := []string{"Cell2", "Cell10", "CellX", "Pack"}
+	if got := m.Labels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Labels() = %v, want %v", got, want)
+	}
+}
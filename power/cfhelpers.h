@@ -0,0 +1,176 @@
+// Safe accessors for CoreFoundation dictionaries shared by the package's cgo
+// files. Everything here is static so that each file's preamble gets its own
+// copy; the helpers follow the CF "Get" rule and never consume or return an
+// owned reference.
+
+#ifndef POWER_CFHELPERS_H
+#define POWER_CFHELPERS_H
+
+#include <stdlib.h>
+#include <string.h>
+#include <CoreFoundation/CoreFoundation.h>
+
+// Helper to safely get a long integer value from a CFDictionary.
+// Returns 0 if key is not found or is not a number.
+static long get_long_prop(CFDictionaryRef dict, const char *key) {
+    CFStringRef key_ref = CFStringCreateWithCString(NULL, key, kCFStringEncodingUTF8);
+    if (!key_ref) return 0;
+
+    long value = 0;
+    CFNumberRef num_ref = (CFNumberRef)CFDictionaryGetValue(dict, key_ref);
+    if (num_ref != NULL && CFGetTypeID(num_ref) == CFNumberGetTypeID()) {
+        CFNumberGetValue(num_ref, kCFNumberSInt64Type, &value);
+    }
+
+    CFRelease(key_ref);
+    return value;
+}
+
+// Helper to check whether a key is present in a CFDictionary at all.
+// Used for optional keys where 0 is a valid value.
+static int has_prop(CFDictionaryRef dict, const char *key) {
+    CFStringRef key_ref = CFStringCreateWithCString(NULL, key, kCFStringEncodingUTF8);
+    if (!key_ref) return 0;
+
+    int present = CFDictionaryContainsKey(dict, key_ref) ? 1 : 0;
+
+    CFRelease(key_ref);
+    return present;
+}
+
+// Helper to safely get a boolean value from a CFDictionary.
+// Returns 0 (false) if key is not found or is not a boolean.
+static int get_bool_prop(CFDictionaryRef dict, const char *key) {
+    CFStringRef key_ref = CFStringCreateWithCString(NULL, key, kCFStringEncodingUTF8);
+    if (!key_ref) return 0;
+
+    int value = 0;
+    CFBooleanRef bool_ref = (CFBooleanRef)CFDictionaryGetValue(dict, key_ref);
+    if (bool_ref != NULL && CFGetTypeID(bool_ref) == CFBooleanGetTypeID()) {
+        value = CFBooleanGetValue(bool_ref);
+    }
+
+    CFRelease(key_ref);
+    return value;
+}
+
+// Helper to safely get a string value from a CFDictionary.
+static void get_string_prop(CFDictionaryRef dict, const char *key, char *buffer, int buffer_size) {
+    CFStringRef key_ref = CFStringCreateWithCString(NULL, key, kCFStringEncodingUTF8);
+    if (!key_ref) { buffer[0] = '\0'; return; }
+
+    CFStringRef str_ref = (CFStringRef)CFDictionaryGetValue(dict, key_ref);
+    if (str_ref == NULL || CFGetTypeID(str_ref) != CFStringGetTypeID() ||
+        !CFStringGetCString(str_ref, buffer, buffer_size, kCFStringEncodingUTF8)) {
+        // CFStringGetCString leaves the buffer undefined when it fails (for
+        // example, when the string doesn't fit).
+        buffer[0] = '\0';
+    }
+    CFRelease(key_ref);
+}
+
+// Helper to copy a string value of any length out of a CFDictionary. The
+// buffer is sized from the string's length, so nothing is truncated. The
+// encoding is always UTF-8, since the result becomes a Go string, and UTF-8
+// can represent every CFString. Returns a malloc'd, NUL-terminated string
+// the caller must free, or NULL if the key is not found, is not a string, or
+// can't be converted.
+static char *copy_string_prop(CFDictionaryRef dict, const char *key) {
+    const CFStringEncoding encoding = kCFStringEncodingUTF8;
+    CFStringRef key_ref = CFStringCreateWithCString(NULL, key, kCFStringEncodingUTF8);
+    if (!key_ref) return NULL;
+
+    CFStringRef str_ref = (CFStringRef)CFDictionaryGetValue(dict, key_ref);
+    CFRelease(key_ref);
+    if (str_ref == NULL || CFGetTypeID(str_ref) != CFStringGetTypeID()) return NULL;
+
+    // Fast path: many CFStrings can hand out their storage directly.
+    const char *direct = CFStringGetCStringPtr(str_ref, encoding);
+    CFIndex size = CFStringGetMaximumSizeForEncoding(CFStringGetLength(str_ref), encoding) + 1;
+    if (direct != NULL) size = (CFIndex)strlen(direct) + 1;
+
+    char *buffer = malloc(size);
+    if (buffer == NULL) return NULL;
+    if (direct != NULL) {
+        memcpy(buffer, direct, size);
+    } else if (!CFStringGetCString(str_ref, buffer, size, encoding)) {
+        free(buffer);
+        return NULL;
+    }
+    return buffer;
+}
+
+// Helper to get a nested dictionary from a parent dictionary.
+// Returns NULL if the key doesn't exist or isn't a dictionary.
+static CFDictionaryRef get_dict_prop(CFDictionaryRef dict, const char *key) {
+    CFStringRef key_ref = CFStringCreateWithCString(NULL, key, kCFStringEncodingUTF8);
+    if (!key_ref) return NULL;
+
+    CFDictionaryRef value = (CFDictionaryRef)CFDictionaryGetValue(dict, key_ref);
+    CFRelease(key_ref);
+
+    if (value != NULL && CFGetTypeID(value) == CFDictionaryGetTypeID()) {
+        return value;
+    }
+    return NULL;
+}
+
+// Helper to get a nested array from a parent dictionary.
+// Returns NULL if the key doesn't exist or isn't an array.
+static CFArrayRef get_array_prop(CFDictionaryRef dict, const char *key) {
+    CFStringRef key_ref = CFStringCreateWithCString(NULL, key, kCFStringEncodingUTF8);
+    if (!key_ref) return NULL;
+
+    CFArrayRef value = (CFArrayRef)CFDictionaryGetValue(dict, key_ref);
+    CFRelease(key_ref);
+
+    if (value != NULL && CFGetTypeID(value) == CFArrayGetTypeID()) {
+        return value;
+    }
+    return NULL;
+}
+
+// Helper to get the length of an array value, or 0 if the key doesn't exist
+// or isn't an array.
+static long get_array_count(CFDictionaryRef dict, const char *key) {
+    CFStringRef key_ref = CFStringCreateWithCString(NULL, key, kCFStringEncodingUTF8);
+    if (!key_ref) return 0;
+
+    CFTypeRef value_ref = CFDictionaryGetValue(dict, key_ref);
+    CFRelease(key_ref);
+
+    if (value_ref != NULL && CFGetTypeID(value_ref) == CFArrayGetTypeID()) {
+        return (long)CFArrayGetCount((CFArrayRef)value_ref);
+    }
+    return 0;
+}
+
+// Helper for parsing arrays.
+static void get_long_array_prop(CFDictionaryRef dict, const char *key, long *out_array, int max_count, int *final_count) {
+    *final_count = 0;
+    CFStringRef key_ref = CFStringCreateWithCString(NULL, key, kCFStringEncodingUTF8);
+    if (!key_ref) return;
+
+    CFTypeRef value_ref = CFDictionaryGetValue(dict, key_ref);
+    CFRelease(key_ref);
+
+    if (value_ref != NULL && CFGetTypeID(value_ref) == CFArrayGetTypeID()) {
+        CFArrayRef array_ref = (CFArrayRef)value_ref;
+        CFIndex count = CFArrayGetCount(array_ref);
+        if (count > max_count) {
+            count = max_count; // Prevent buffer overflow
+        }
+        *final_count = (int)count;
+
+        for (CFIndex i = 0; i < count; i++) {
+            CFNumberRef num_ref = (CFNumberRef)CFArrayGetValueAtIndex(array_ref, i);
+            if (num_ref != NULL && CFGetTypeID(num_ref) == CFNumberGetTypeID()) {
+                CFNumberGetValue(num_ref, kCFNumberSInt64Type, &out_array[i]);
+            } else {
+                out_array[i] = 0; // Default value if type is wrong
+            }
+        }
+    }
+}
+
+#endif // POWER_CFHELPERS_H
@@ -0,0 +1,87 @@
+package power
+
+import (
+	"math"
+	"time"
+)
+
+// ChargeFraction returns the state of charge as CurrentCapacity divided by
+// MaxCapacity, a 0–1 value with full precision. It is the package's single
+// definition of state of charge; the whole-number percentages elsewhere are
+// rounded from it. It returns 0 if MaxCapacity is unknown.
+func (b *BatteryInfo) ChargeFraction() float64 {
+	if b.Battery.MaxCapacity <= 0 {
+		return 0
+	}
+	return float64(b.Battery.CurrentCapacity) / float64(b.Battery.MaxCapacity)
+}
+
+// DischargeCRate returns the discharge current relative to the battery's
+// present full-charge capacity, the C-rate: the discharge current in Amps
+// divided by MaxCapacity in Ah (MaxCapacity is in mAh, hence the factor of
+// 1000). A rate of 1 would empty a full battery in an hour; sustained rates
+// well above 1 stress the pack. It returns 0 when the battery isn't
+// discharging, the capacity is unknown or the amperage isn't finite.
+func (b *BatteryInfo) DischargeCRate() float64 {
+	amperage := finite(b.appleAmperage())
+	if amperage >= 0 || b.Battery.MaxCapacity <= 0 {
+		return 0
+	}
+	capacityAh := float64(b.Battery.MaxCapacity) / 1000.0
+	return -amperage / capacityAh
+}
+
+// chargePercent returns ChargeFraction as a whole percentage.
+func (b *BatteryInfo) chargePercent() int {
+	return int(math.Round(b.ChargeFraction() * 100.0))
+}
+
+// ChargePercent returns the state of charge as a whole percentage, the same
+// figure ChargeFraction gives for a full snapshot. It is the cheapest live
+// read in the package: only the two capacity properties are fetched from the
+// registry entry, with no property table copy and no nested dictionaries,
+// which suits polling at 1 Hz or faster. Like GetBatteryInfo, it falls back
+// to MaxCapacity, converted from a percentage where needed, on machines
+// without AppleRawMaxCapacity.
+func ChargePercent() (int, error) {
+	current, maxCapacity, err := readChargeCapacities()
+	if err != nil {
+		return 0, err
+	}
+	info := BatteryInfo{Battery: Battery{CurrentCapacity: current, MaxCapacity: maxCapacity}}
+	return info.chargePercent(), nil
+}
+
+// TimeToChargePercent estimates how long until the battery reaches target
+// percent of its present full-charge capacity, from the capacity still to go
+// and the current charging amperage. Unlike Battery.TimeToFull, which the
+// firmware computes for 100%, it suits charge-limited workflows (e.g.,
+// "time until 80%").
+//
+// The estimate assumes the present current holds, so it is optimistic once
+// charging tapers near the top of the pack. ok is false when the battery is
+// not charging, is already at or past target, or the capacity or amperage is
+// unknown.
+func (b *BatteryInfo) TimeToChargePercent(target int) (eta time.Duration, ok bool) {
+	if target <= 0 || target > 100 || b.Battery.MaxCapacity <= 0 {
+		return 0, false
+	}
+	chargingMA := finite(b.appleAmperage()) * 1000.0
+	if chargingMA <= 0 {
+		return 0, false
+	}
+
+	targetMAh := float64(b.Battery.MaxCapacity) * float64(target) / 100.0
+	gapMAh := targetMAh - float64(b.Battery.CurrentCapacity)
+	if gapMAh <= 0 {
+		return 0, false
+	}
+
+	// A trickle of current gives an ETA too long to represent; treat it as
+	// unknown rather than overflowing the Duration.
+	hours := gapMAh / chargingMA
+	if hours >= math.MaxInt64/float64(time.Hour) {
+		return 0, false
+	}
+	return time.Duration(hours * float64(time.Hour)), true
+}
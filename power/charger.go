@@ -0,0 +1,14 @@
+package power
+
+// ChargeVoltageHeadroom returns how far the charger's target voltage sits
+// above the measured pack voltage, as Charger.ChargingVoltage −
+// Battery.Voltage in Volts. It is large early in a charge, when the charger
+// is pushing hard, and shrinks to near zero as the pack reaches its charge
+// voltage and the current tapers. ok is false when ChargerData isn't
+// reported or either voltage is unknown.
+func (b *BatteryInfo) ChargeVoltageHeadroom() (volts float64, ok bool) {
+	if !b.Has(CapChargerData) || b.Charger.ChargingVoltage <= 0 || b.Battery.Voltage <= 0 {
+		return 0, false
+	}
+	return b.Charger.ChargingVoltage - b.Battery.Voltage, true
+}
@@ -0,0 +1,62 @@
+package power
+
+import (
+	"sync"
+	"time"
+)
+
+// coalescedCall is a GetBatteryInfo read shared by every caller that arrives
+// while it is in flight.
+type coalescedCall struct {
+	done chan struct{}
+	info *BatteryInfo
+	err  error
+}
+
+var coalesced struct {
+	mu   sync.Mutex
+	last *BatteryInfo // most recent successful read
+	call *coalescedCall
+}
+
+// GetBatteryInfoCoalesced returns a snapshot no older than maxAge, measured
+// from its ReadAt. If the most recent read is fresh enough it is returned
+// without touching IOKit; otherwise a single read is performed and shared
+// with every concurrent caller, cutting redundant CGO crossings in high-fanout
+// code. Each caller receives its own copy of the snapshot.
+//
+// Failed reads are shared with the callers waiting on them but never cached.
+// GetBatteryInfoCoalesced is safe for concurrent use.
+func GetBatteryInfoCoalesced(maxAge time.Duration) (*BatteryInfo, error) {
+	coalesced.mu.Lock()
+	if last := coalesced.last; last != nil && time.Since(last.ReadAt) < maxAge {
+		coalesced.mu.Unlock()
+		return last.clone(), nil
+	}
+	if call := coalesced.call; call != nil {
+		coalesced.mu.Unlock()
+		<-call.done
+		if call.err != nil {
+			return nil, call.err
+		}
+		return call.info.clone(), nil
+	}
+	call := &coalescedCall{done: make(chan struct{})}
+	coalesced.call = call
+	coalesced.mu.Unlock()
+
+	call.info, call.err = GetBatteryInfo()
+
+	coalesced.mu.Lock()
+	coalesced.call = nil
+	if call.err == nil {
+		coalesced.last = call.info
+	}
+	coalesced.mu.Unlock()
+	close(call.done)
+
+	if call.err != nil {
+		return nil, call.err
+	}
+	return call.info.clone(), nil
+}
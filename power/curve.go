@@ -0,0 +1,74 @@
+package power
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+)
+
+// chargeCurvePollInterval is how often CaptureChargeCurve samples the battery.
+const chargeCurvePollInterval = 10 * time.Second
+
+// CaptureChargeCurve records a charging session as CSV with the columns
+// percent, battery_watts and time (RFC 3339). A row is written for the first
+// sample and then each time the charge percentage has advanced by at least
+// step since the last row, which captures the tapering curve without
+// flooding the output. Capture stops, after writing a final row, once the
+// battery reports FullyCharged.
+//
+// CaptureChargeCurve blocks until the battery is full, the context is done
+// (returning ctx.Err()), or a read or write fails.
+func CaptureChargeCurve(ctx context.Context, w io.Writer, step int) error {
+	if step <= 0 {
+		return errors.New("power: step must be positive")
+	}
+
+	cw := csv.NewWriter(w)
+	writeRow := func(record []string) error {
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	if err := writeRow([]string{"percent", "battery_watts", "time"}); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(chargeCurvePollInterval)
+	defer ticker.Stop()
+
+	lastPercent := -1
+	for {
+		info, err := GetBatteryInfo()
+		if err != nil {
+			return err
+		}
+
+		percent := info.chargePercent()
+		full := info.State.FullyCharged
+		if lastPercent < 0 || percent-lastPercent >= step || full {
+			err := writeRow([]string{
+				strconv.Itoa(percent),
+				strconv.FormatFloat(info.Calculations.BatteryPower, 'f', 2, 64),
+				info.ReadAt.Format(time.RFC3339),
+			})
+			if err != nil {
+				return err
+			}
+			lastPercent = percent
+		}
+		if full {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package power
+
+// DetailedBatteryInfo is a snapshot together with every raw property of the
+// AppleSmartBattery entry, for diagnostic tools that want the keys this
+// package doesn't model.
+type DetailedBatteryInfo struct {
+	// Info is the snapshot, as returned by GetBatteryInfo.
+	Info *BatteryInfo
+
+	// Properties holds every property of the AppleSmartBattery entry,
+	// decoded as by GetPropertiesAtPath, including nested dictionaries in
+	// full. It is nil if the properties couldn't be read.
+	Properties map[string]any
+}
+
+// GetBatteryInfoDetailed reads a snapshot and, alongside it, every raw
+// property of the AppleSmartBattery entry, such as the wait timers, update
+// counters and lifetime statistics that BatteryInfo leaves out. Properties
+// that can't be read leave Properties nil rather than failing the call; only
+// a failed snapshot read does.
+//
+// Running as root makes no difference. IOKit doesn't restrict the battery's
+// properties by privilege, IORegistryEntryCreateCFProperties takes no options
+// that reveal more of them, and kIORegistryIterateRecursively only widens a
+// search across parent and child entries, not the keys of this one. So no
+// extra BatteryInfo fields become available; the extra detail is all in
+// Properties. The two reads are separate, so their values can be a poll
+// apart.
+func GetBatteryInfoDetailed() (*DetailedBatteryInfo, error) {
+	info, err := GetBatteryInfo()
+	if err != nil {
+		return nil, err
+	}
+	detailed := &DetailedBatteryInfo{Info: info}
+	data, err := copyServicePropertiesXML(ServiceAppleSmartBattery)
+	if err != nil {
+		return detailed, nil
+	}
+	if value, err := parsePlistXML(data); err == nil {
+		detailed.Properties, _ = value.(map[string]any)
+	}
+	return detailed, nil
+}
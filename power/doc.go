@@ -0,0 +1,7 @@
+// Package power provides direct access to macOS IOKit power and battery telemetry.
+//
+// Reading live telemetry requires macOS and cgo. On other platforms, or with
+// cgo disabled, the functions that talk to IOKit return ErrUnsupported, while
+// the types, calculations and analysis helpers remain available for working
+// with snapshots captured elsewhere.
+package power
@@ -0,0 +1,76 @@
+package power
+
+import "time"
+
+// DefaultEnergyMaxGap is the longest interval between two samples that an
+// EnergyCounter will integrate. Longer gaps usually mean the machine slept,
+// and integrating across them would attribute a large, bogus chunk of energy
+// to a single reading.
+const DefaultEnergyMaxGap = 5 * time.Minute
+
+// EnergyCounter turns a stream of BatteryInfo snapshots into cumulative energy
+// totals by integrating BatteryPower over the time between samples.
+//
+// The zero value is ready to use. An EnergyCounter is not safe for concurrent
+// use.
+type EnergyCounter struct {
+	// ChargedWh is the total energy that has flowed into the battery, in Wh.
+	ChargedWh float64
+
+	// DischargedWh is the total energy that has flowed out of the battery, in
+	// Wh. It is always reported as a positive number.
+	DischargedWh float64
+
+	lastAt    time.Time
+	lastPower float64
+	hasLast   bool
+}
+
+// Add records a snapshot taken at the given time. The interval since the
+// previous snapshot is integrated using the average of the two BatteryPower
+// readings; charging is added to ChargedWh and discharging to DischargedWh,
+// whichever sign convention the snapshot was read with. Intervals that are
+// not positive or exceed DefaultEnergyMaxGap are skipped, and the snapshot
+// becomes the new starting point.
+func (e *EnergyCounter) Add(info *BatteryInfo, at time.Time) {
+	e.AddWithGapDetection(info, at, DefaultEnergyMaxGap)
+}
+
+// AddWithGapDetection is like Add, but treats any interval longer than maxGap
+// as the machine having slept: nothing is integrated across it and the
+// snapshot starts a new interval. Callers sampling at a known rate can pass a
+// small multiple of their interval to catch short sleeps that
+// DefaultEnergyMaxGap would let through. A non-positive maxGap means
+// DefaultEnergyMaxGap.
+func (e *EnergyCounter) AddWithGapDetection(info *BatteryInfo, at time.Time, maxGap time.Duration) {
+	if info == nil {
+		return
+	}
+	if maxGap <= 0 {
+		maxGap = DefaultEnergyMaxGap
+	}
+	power := info.Calculations.appleBatteryPower()
+
+	if e.hasLast {
+		elapsed := at.Sub(e.lastAt)
+		if elapsed > 0 && elapsed <= maxGap {
+			wh := (e.lastPower + power) / 2 * elapsed.Hours()
+			if wh >= 0 {
+				e.ChargedWh += wh
+			} else {
+				e.DischargedWh -= wh
+			}
+		}
+	}
+
+	e.lastAt = at
+	e.lastPower = power
+	e.hasLast = true
+}
+
+// Reset clears the totals and the starting point, so that the next Add
+// begins a new measurement window. Use it at session boundaries such as
+// waking from sleep.
+func (e *EnergyCounter) Reset() {
+	*e = EnergyCounter{}
+}
@@ -0,0 +1,53 @@
+package power
+
+// NominalCellVoltage is the nominal voltage of a single lithium-ion cell in
+// Volts, as used on Apple's battery labels (e.g., 11.4V for a three-cell
+// pack). DesignEnergyWh multiplies it by the number of cells in series.
+const NominalCellVoltage = 3.8
+
+// EnergyWh returns the energy currently stored in the battery in Wh, as
+// CurrentCapacity (mAh) × Voltage (V) / 1000. It uses the measured pack
+// voltage, which sits above nominal when full and below it when nearly
+// empty, so it tracks the live state of the pack rather than Apple's label
+// figures. It returns 0 if either reading is missing or the voltage isn't
+// finite.
+func (b *BatteryInfo) EnergyWh() float64 {
+	return float64(b.Battery.CurrentCapacity) * finite(b.Battery.Voltage) / 1000.0
+}
+
+// DesignEnergyWh returns the battery's design energy in Wh, as
+// DesignCapacity (mAh) × the pack's nominal voltage / 1000. The nominal
+// voltage is NominalCellVoltage times the number of cells reported in
+// IndividualCellVoltages, not the measured Voltage, so the result is a fixed
+// property of the pack that matches the Wh rating printed on it to within
+// rounding. It returns 0 if the design capacity or the cell count is unknown.
+func (b *BatteryInfo) DesignEnergyWh() float64 {
+	return float64(b.Battery.DesignCapacity) * b.nominalVoltage() / 1000.0
+}
+
+// nominalVoltage returns the pack's nominal voltage, NominalCellVoltage times
+// the number of cells in series, or 0 if the cell count is unknown.
+func (b *BatteryInfo) nominalVoltage() float64 {
+	return NominalCellVoltage * float64(len(b.Battery.IndividualCellVoltages))
+}
+
+// EnergyDelta returns the change in stored energy from snapshot a to snapshot
+// b in Wh, as (b.CurrentCapacity − a.CurrentCapacity) × the pack's nominal
+// voltage / 1000; it is positive when the battery gained charge. Because it
+// compares the gauge's own charge counts, it is more accurate over long or
+// sparse intervals than integrating BatteryPower with an EnergyCounter, and
+// the two make a useful cross-check. Using the nominal rather than the
+// measured voltage keeps a round trip to the same charge at zero.
+//
+// Both snapshots should come from the same battery. It returns 0 if either
+// is nil or neither reports its cell count.
+func EnergyDelta(a, b *BatteryInfo) float64 {
+	if a == nil || b == nil {
+		return 0
+	}
+	voltage := b.nominalVoltage()
+	if voltage == 0 {
+		voltage = a.nominalVoltage()
+	}
+	return float64(b.Battery.CurrentCapacity-a.Battery.CurrentCapacity) * voltage / 1000.0
+}
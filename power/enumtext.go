@@ -0,0 +1,28 @@
+package power
+
+import "fmt"
+
+// Enum types in this package marshal as their String names so that JSON and
+// other text encodings stay readable and don't change meaning if constants
+// are reordered. New enums should implement encoding.TextMarshaler and
+// encoding.TextUnmarshaler with these helpers.
+
+// enumText returns the text form of an enum whose names table is indexed by
+// its value.
+func enumText[T ~int](v T, names []string) ([]byte, error) {
+	if v < 0 || int(v) >= len(names) {
+		return nil, fmt.Errorf("power: invalid %T value %d", v, int(v))
+	}
+	return []byte(names[v]), nil
+}
+
+// parseEnumText is the inverse of enumText.
+func parseEnumText[T ~int](text []byte, names []string) (T, error) {
+	for i, name := range names {
+		if name == string(text) {
+			return T(i), nil
+		}
+	}
+	var zero T
+	return zero, fmt.Errorf("power: unknown %T %q", zero, text)
+}
@@ -0,0 +1,71 @@
+package power
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnsupported is returned by functions that read live telemetry when the
+// package is built for a platform other than macOS, or with cgo disabled.
+var ErrUnsupported = errors.New("power: IOKit telemetry requires macOS and cgo")
+
+// ErrServiceVanished reports that no AppleSmartBattery service could be
+// matched in the I/O Registry. Reads fail with an error matching it (via
+// errors.Is) while the service is gone, and Watch sends it on its error
+// channel when a previously working read starts failing this way.
+var ErrServiceVanished = errors.New("power: AppleSmartBattery service not found")
+
+// ErrAmperagePolarity is returned by Validate when the sign of the reported
+// amperage contradicts the change in stored charge, which usually means the
+// machine reports Amperage with the opposite sign convention.
+var ErrAmperagePolarity = errors.New("power: amperage sign disagrees with capacity change")
+
+// MissingKeyError reports an expected IOKit property that the battery didn't
+// provide. It is only returned with Options.Strict.
+type MissingKeyError struct {
+	// Key is the missing property, e.g. "BatteryData".
+	Key string
+}
+
+func (e *MissingKeyError) Error() string {
+	return "power: missing IOKit key " + e.Key
+}
+
+// MultiError collects several independent failures from one read, so they
+// can all be reported at once. errors.Is and errors.As see every one of them.
+type MultiError struct {
+	Errors []error
+}
+
+func (m *MultiError) Error() string {
+	msgs := make([]string, len(m.Errors))
+	for i, err := range m.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the collected errors.
+func (m *MultiError) Unwrap() []error {
+	return m.Errors
+}
+
+// ioErrNoService is the get_battery_info return code for a failed service
+// match.
+const ioErrNoService = 3
+
+// ioKitError wraps a non-zero return code from the C read.
+type ioKitError struct {
+	code int
+}
+
+func (e *ioKitError) Error() string {
+	return fmt.Sprintf("IOKit query failed with C error code: %d", e.code)
+}
+
+// Is lets a failed service match be detected with errors.Is(err,
+// ErrServiceVanished).
+func (e *ioKitError) Is(target error) bool {
+	return target == ErrServiceVanished && e.code == ioErrNoService
+}
@@ -0,0 +1,96 @@
+package power
+
+import "context"
+
+// EventKind identifies a power state transition reported by WatchEvents.
+type EventKind int
+
+const (
+	// PluggedIn is sent when external power is connected.
+	PluggedIn EventKind = iota
+
+	// Unplugged is sent when external power is disconnected.
+	Unplugged
+)
+
+// eventKindNames is indexed by EventKind.
+var eventKindNames = []string{
+	PluggedIn: "plugged-in",
+	Unplugged: "unplugged",
+}
+
+// String returns a lower-case name for the event, such as "unplugged".
+func (k EventKind) String() string {
+	if k < 0 || int(k) >= len(eventKindNames) {
+		return "unknown"
+	}
+	return eventKindNames[k]
+}
+
+// MarshalText implements encoding.TextMarshaler using the String names.
+func (k EventKind) MarshalText() ([]byte, error) {
+	return enumText(k, eventKindNames)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *EventKind) UnmarshalText(text []byte) error {
+	v, err := parseEnumText[EventKind](text, eventKindNames)
+	if err != nil {
+		return err
+	}
+	*k = v
+	return nil
+}
+
+// Event is a power state transition together with the snapshot that
+// revealed it.
+type Event struct {
+	Kind EventKind
+
+	// Info was read inside the power source notification callback, so it
+	// reflects the moment of the transition rather than a later poll.
+	Info *BatteryInfo
+}
+
+// WatchEvents emits an Event each time external power is connected or
+// disconnected. It is driven by the IOPSNotificationCreateRunLoopSource
+// notification: on every notification a snapshot is read immediately, on
+// the notification thread, and compared with the previous one, so an
+// Unplugged event carries the discharge-start conditions before they have
+// had time to shift. The state at the time of the call only establishes the
+// baseline and is not reported. Notifications whose read fails are skipped.
+// The channel is closed once ctx is done.
+//
+// WatchEvents is safe for concurrent use; each call gets its own run loop
+// thread.
+func WatchEvents(ctx context.Context) (<-chan Event, error) {
+	out := make(chan Event, 4)
+	var connected, known bool
+
+	publish := func() {
+		info, err := GetBatteryInfo()
+		if err != nil {
+			return
+		}
+		wasConnected, wasKnown := connected, known
+		connected, known = info.State.IsConnected, true
+		if !wasKnown || connected == wasConnected {
+			return
+		}
+
+		kind := Unplugged
+		if connected {
+			kind = PluggedIn
+		}
+		select {
+		case out <- Event{Kind: kind, Info: info}:
+		case <-ctx.Done():
+		}
+	}
+
+	err := watchPowerSources(ctx, publish, func() { close(out) })
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,60 @@
+package power
+
+import "math"
+
+// minFadePoints is the fewest distinct cycle counts PredictCapacityAtCycle
+// fits a line through.
+const minFadePoints = 2
+
+// CapacityPoint is one observation of full-charge capacity at a cycle count,
+// as recorded in a battery's history.
+type CapacityPoint struct {
+	CycleCount  int
+	MaxCapacity int // in mAh
+}
+
+// CapacityPoint returns the snapshot's cycle count and full-charge capacity,
+// ready to append to a history for PredictCapacityAtCycle.
+func (b *BatteryInfo) CapacityPoint() CapacityPoint {
+	return CapacityPoint{CycleCount: b.Battery.CycleCount, MaxCapacity: b.Battery.MaxCapacity}
+}
+
+// PredictCapacityAtCycle projects the full-charge capacity, in mAh, that the
+// battery will have at the given cycle count, by a least-squares linear fit
+// of MaxCapacity against CycleCount over history. Lithium-ion fade is close
+// to linear through the rated life of a pack, but accelerates near its end,
+// so projections well past the rated cycle count are optimistic. The result
+// is floored at 0.
+//
+// ok is false when history has fewer than two distinct cycle counts.
+// Points with an unknown (zero) capacity are ignored, and so are any
+// recalibration jumps in the data, only to the extent the fit averages
+// them out.
+func PredictCapacityAtCycle(history []CapacityPoint, cycle int) (capacity int, ok bool) {
+	var n, sumX, sumY, sumXY, sumXX float64
+	seen := map[int]bool{}
+	for _, p := range history {
+		if p.MaxCapacity <= 0 {
+			continue
+		}
+		x, y := float64(p.CycleCount), float64(p.MaxCapacity)
+		n++
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+		seen[p.CycleCount] = true
+	}
+	if len(seen) < minFadePoints {
+		return 0, false
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, false
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+	predicted := finite(intercept + slope*float64(cycle))
+	return max(int(math.Round(predicted)), 0), true
+}
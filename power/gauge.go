@@ -0,0 +1,82 @@
+package power
+
+import "strings"
+
+// gaugeConditionFlag is the Smart Battery Data "condition flag" bit of the
+// gauge status word, set when the gauge wants a full-discharge calibration
+// cycle to relearn its capacity.
+const gaugeConditionFlag = 0x0080
+
+// GaugeChip identifies a known battery gas-gauge (BMS) chip.
+type GaugeChip int
+
+const (
+	// UnknownGauge is returned for gauge names not in the table. The raw
+	// name is still available in Battery.DeviceName.
+	UnknownGauge GaugeChip = iota
+	GaugeBQ20Z45
+	GaugeBQ20Z451
+	GaugeBQ40Z651
+	GaugeBQ40Z655
+)
+
+// gaugeChipInfo describes a GaugeChip. The table is indexed by GaugeChip, so
+// add new chips at the end of both the constants and the table.
+var gaugeChipInfo = []struct {
+	name         string
+	manufacturer string
+}{
+	UnknownGauge:  {"unknown", ""},
+	GaugeBQ20Z45:  {"bq20z45", "Texas Instruments"},
+	GaugeBQ20Z451: {"bq20z451", "Texas Instruments"},
+	GaugeBQ40Z651: {"bq40z651", "Texas Instruments"},
+	GaugeBQ40Z655: {"bq40z655", "Texas Instruments"},
+}
+
+// ParseGaugeChip maps a DeviceName string such as "bq40z651" to its
+// GaugeChip, ignoring case and surrounding whitespace. Unrecognized names map
+// to UnknownGauge.
+func ParseGaugeChip(name string) GaugeChip {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for chip, info := range gaugeChipInfo {
+		if GaugeChip(chip) != UnknownGauge && info.name == name {
+			return GaugeChip(chip)
+		}
+	}
+	return UnknownGauge
+}
+
+// String returns the chip's part name, or "unknown".
+func (g GaugeChip) String() string {
+	if g < 0 || int(g) >= len(gaugeChipInfo) {
+		return gaugeChipInfo[UnknownGauge].name
+	}
+	return gaugeChipInfo[g].name
+}
+
+// Manufacturer returns the chip vendor (e.g., "Texas Instruments"), or an
+// empty string for UnknownGauge.
+func (g GaugeChip) Manufacturer() string {
+	if g < 0 || int(g) >= len(gaugeChipInfo) {
+		return ""
+	}
+	return gaugeChipInfo[g].manufacturer
+}
+
+// GaugeChip returns the battery's gas-gauge chip parsed from DeviceName.
+func (b *BatteryInfo) GaugeChip() GaugeChip {
+	return ParseGaugeChip(b.Battery.DeviceName)
+}
+
+// MarshalText implements encoding.TextMarshaler using the chip's part name.
+func (g GaugeChip) MarshalText() ([]byte, error) {
+	return []byte(g.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Like ParseGaugeChip, it
+// maps unrecognized names to UnknownGauge rather than failing, since new
+// hardware may report gauges this version doesn't know.
+func (g *GaugeChip) UnmarshalText(text []byte) error {
+	*g = ParseGaugeChip(string(text))
+	return nil
+}
@@ -0,0 +1,73 @@
+package power
+
+import (
+	"strings"
+	"unicode"
+)
+
+// applePackMakers are manufacturer codes seen on the packs Apple ships.
+var applePackMakers = map[string]bool{
+	"smp": true, // Simplo
+	"dsy": true, // Desay
+	"swd": true, // Sunwoda
+	"atl": true, // Amperex
+	"sdi": true, // Samsung SDI
+	"lgc": true, // LG Chem
+}
+
+// genuineSignal is one piece of evidence for IsGenuineApple.
+type genuineSignal struct {
+	weight     float64
+	applicable bool // whether the snapshot carries this evidence at all
+	genuine    bool
+}
+
+// IsGenuineApple guesses whether the installed battery is an Apple part or
+// a third-party replacement, and returns the confidence (0–1) that it is
+// genuine; genuine is true when the confidence is at least 0.5.
+//
+// This is a heuristic, not an authentication. It weighs a recognised Apple
+// gas-gauge chip, the presence of the nested BatteryData details that
+// aftermarket gauges often omit, a serial number in Apple's 16-plus
+// character upper-case alphanumeric format, a programmed chemistry ID, and,
+// where reported, a manufacturer code used on Apple packs. A good
+// replacement can pass every check and a genuine pack with a failing gauge
+// can miss some, so treat the result as a hint for further inspection.
+func (b *BatteryInfo) IsGenuineApple() (genuine bool, confidence float64) {
+	signals := []genuineSignal{
+		{0.30, true, b.GaugeChip() != UnknownGauge},
+		{0.25, true, len(b.Battery.IndividualCellVoltages) > 0 || b.Has(CapGaugeStatus)},
+		{0.20, true, isAppleSerial(b.Battery.SerialNumber)},
+		{0.10, true, b.Battery.ChemID != 0},
+		{0.15, b.Battery.Manufacturer != "", applePackMakers[strings.ToLower(strings.TrimSpace(b.Battery.Manufacturer))]},
+	}
+
+	var score, total float64
+	for _, s := range signals {
+		if !s.applicable {
+			continue
+		}
+		total += s.weight
+		if s.genuine {
+			score += s.weight
+		}
+	}
+	if total == 0 {
+		return false, 0
+	}
+	confidence = score / total
+	return confidence >= 0.5, confidence
+}
+
+// isAppleSerial reports whether s looks like an Apple battery serial number.
+func isAppleSerial(s string) bool {
+	if len(s) < 16 {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsUpper(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package power_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/peterneutron/go-iokit-powertelemetry/power"
+)
+
+// The golden fixtures in testdata/golden are snapshots seeded from
+// iokittest.FakeBatteryInfo and from ioreg dumps, plus any captured on real
+// Macs by contributors. Each NAME.json is replayed through Recalculate and
+// must reproduce its recorded Calculations; when a NAME.ioreg dump sits next
+// to it, ParseIORegDump of the dump must reproduce them too.
+//
+// To add a fixture from the Mac you are on (the serial number is redacted):
+//
+//	POWERTELEMETRY_GOLDEN_CAPTURE=1 go test -run TestGoldenCapture ./power
+//
+// After an intended change to a calculation, rewrite the recorded
+// Calculations of every fixture and review the diff:
+//
+//	POWERTELEMETRY_GOLDEN_UPDATE=1 go test -run TestGolden ./power
+const (
+	goldenDir        = "testdata/golden"
+	goldenCaptureEnv = "POWERTELEMETRY_GOLDEN_CAPTURE"
+	goldenUpdateEnv  = "POWERTELEMETRY_GOLDEN_UPDATE"
+)
+
+func TestGolden(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(goldenDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no golden fixtures in %s", goldenDir)
+	}
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var info power.BatteryInfo
+			if err := json.Unmarshal(data, &info); err != nil {
+				t.Fatal(err)
+			}
+			want := marshalCalculations(t, info.Calculations)
+
+			info.Recalculate(power.Options{})
+			got := marshalCalculations(t, info.Calculations)
+			if os.Getenv(goldenUpdateEnv) != "" {
+				writeGolden(t, path, &info)
+				return
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Recalculate:\n got  %s\n want %s", got, want)
+			}
+
+			dump, err := os.Open(strings.TrimSuffix(path, ".json") + ".ioreg")
+			if os.IsNotExist(err) {
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer dump.Close()
+			parsed, err := power.ParseIORegDump(dump)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := marshalCalculations(t, parsed.Calculations); !bytes.Equal(got, want) {
+				t.Errorf("ParseIORegDump:\n got  %s\n want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestGoldenCapture(t *testing.T) {
+	if os.Getenv(goldenCaptureEnv) == "" {
+		t.Skipf("set %s=1 to capture a fixture from this machine", goldenCaptureEnv)
+	}
+	info, err := power.GetBatteryInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	info.Battery.SerialNumber = "REDACTED"
+
+	name := strings.ToLower(info.Battery.DeviceName)
+	if name == "" {
+		name = "unknown"
+	}
+	path := filepath.Join(goldenDir, name+"-"+info.ReadAt.UTC().Format("20060102T150405Z")+".json")
+	writeGolden(t, path, info)
+	t.Logf("wrote %s", path)
+}
+
+// marshalCalculations encodes c for comparison, so that only the exported,
+// recorded fields take part.
+func marshalCalculations(t *testing.T, c power.Calculations) []byte {
+	t.Helper()
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// writeGolden writes info to path as an indented fixture.
+func writeGolden(t *testing.T, path string, info *power.BatteryInfo) {
+	t.Helper()
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,39 @@
+package power
+
+// healthGrades are the letters HealthGrade can return, best first.
+var healthGrades = [...]string{"A", "B", "C", "D", "F"}
+
+// HealthGrade rates the battery with a letter from A to F using the default
+// thresholds. See HealthGradeWithOptions.
+func (b *BatteryInfo) HealthGrade() string {
+	return b.HealthGradeWithOptions(Options{})
+}
+
+// HealthGradeWithOptions rates the battery with a letter from A to F for
+// display. The letter comes from ConditionAdjustedHealth compared against
+// opts.HealthGradeThresholds (by default A ≥ 90, B ≥ 80, C ≥ 70, D ≥ 60,
+// otherwise F), and is lowered by one step when the cycle count exceeds the
+// battery's rated cycles (DesignCycleCount, or opts.WarrantyCycleLimit for
+// unknown gauges). It returns "" when the design capacity is unknown.
+func (b *BatteryInfo) HealthGradeWithOptions(opts Options) string {
+	if b.Battery.DesignCapacity <= 0 {
+		return ""
+	}
+
+	grade := len(healthGrades) - 1
+	for i, threshold := range opts.healthGradeThresholds() {
+		if b.Calculations.ConditionAdjustedHealth >= threshold {
+			grade = i
+			break
+		}
+	}
+
+	rated, ok := DesignCycleCount(b.Battery.DeviceName)
+	if !ok {
+		rated = opts.warrantyCycleLimit()
+	}
+	if b.Battery.CycleCount > rated {
+		grade = min(grade+1, len(healthGrades)-1)
+	}
+	return healthGrades[grade]
+}
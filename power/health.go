@@ -0,0 +1,83 @@
+package power
+
+import (
+	"fmt"
+	"math"
+)
+
+// WarrantyFlag applies Apple's battery service criteria with the default
+// thresholds. See WarrantyFlagWithOptions.
+func (b *BatteryInfo) WarrantyFlag() (needsService bool, reasons []string) {
+	return b.WarrantyFlagWithOptions(Options{})
+}
+
+// WarrantyFlagWithOptions reports whether the battery should be serviced and
+// explains the decision. A battery needs service when its gauge has latched a
+// permanent failure, when it retains less than WarrantyMinHealth percent of
+// its design capacity, or when it has exceeded WarrantyCycleLimit cycles.
+// Health below the minimum within the cycle limit is called out as covered.
+//
+// The reasons slice always explains the outcome, including when no service is
+// needed.
+func (b *BatteryInfo) WarrantyFlagWithOptions(opts Options) (needsService bool, reasons []string) {
+	minHealth := opts.warrantyMinHealth()
+	cycleLimit := opts.warrantyCycleLimit()
+	health := b.Calculations.HealthByMaxCapacity
+	cycles := b.Battery.CycleCount
+
+	if b.Battery.PermanentFailureStatus != 0 {
+		needsService = true
+		reasons = append(reasons, fmt.Sprintf("permanent failure reported (status 0x%x)", b.Battery.PermanentFailureStatus))
+	}
+
+	if b.Battery.DesignCapacity <= 0 {
+		reasons = append(reasons, "design capacity unknown; health not evaluated")
+	} else if health < minHealth {
+		needsService = true
+		if cycles <= cycleLimit {
+			reasons = append(reasons, fmt.Sprintf("health %d%% is below %d%% within %d cycles (%d used); covered by service criteria", health, minHealth, cycleLimit, cycles))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("health %d%% is below %d%% after %d cycles", health, minHealth, cycles))
+		}
+	}
+
+	if cycles > cycleLimit {
+		needsService = true
+		reasons = append(reasons, fmt.Sprintf("cycle count %d exceeds rated %d", cycles, cycleLimit))
+	}
+
+	if !needsService && b.Battery.DesignCapacity > 0 {
+		reasons = append(reasons, fmt.Sprintf("health %d%% at %d of %d cycles meets service criteria", health, cycles, cycleLimit))
+	}
+	return needsService, reasons
+}
+
+// RecalibrationThreshold is the single-sample change in MaxCapacity or
+// NominalCapacity, as a percentage of the previous value, above which
+// DetectRecalibration reports a gauge recalibration.
+const RecalibrationThreshold = 5.0
+
+// DetectRecalibration reports whether the change between two consecutive
+// snapshots looks like a gas-gauge recalibration rather than real wear: a jump
+// of more than RecalibrationThreshold percent in MaxCapacity or
+// NominalCapacity, in either direction. Health logs can use it to suppress
+// spurious "health improved 7%" events. It returns false if either snapshot
+// is nil.
+func DetectRecalibration(prev, cur *BatteryInfo) bool {
+	if prev == nil || cur == nil {
+		return false
+	}
+	return capacityJumped(prev.Battery.MaxCapacity, cur.Battery.MaxCapacity) ||
+		capacityJumped(prev.Battery.NominalCapacity, cur.Battery.NominalCapacity)
+}
+
+// capacityJumped reports whether cur differs from prev by more than
+// RecalibrationThreshold percent. An unknown (zero) previous value never
+// counts as a jump.
+func capacityJumped(prev, cur int) bool {
+	if prev <= 0 {
+		return false
+	}
+	change := math.Abs(float64(cur-prev)) / float64(prev) * 100.0
+	return change > RecalibrationThreshold
+}
@@ -0,0 +1,91 @@
+package power
+
+import "math"
+
+// HealthModel selects a definition of battery health for Health.
+type HealthModel int
+
+const (
+	// ByMaxCapacity is Calculations.HealthByMaxCapacity: the gauge's present
+	// full-charge capacity against the design capacity.
+	ByMaxCapacity HealthModel = iota
+
+	// ByNominalCapacity is Calculations.HealthByNominalCapacity, based on
+	// the less volatile nominal full-charge capacity.
+	ByNominalCapacity
+
+	// ConditionAdjusted is Calculations.ConditionAdjustedHealth, the nominal
+	// health adjusted for cell voltage drift.
+	ConditionAdjusted
+
+	// AppleOfficial approximates the "Maximum Capacity" figure shown in
+	// System Settings; see BatteryInfo.SystemSettingsHealthEstimate.
+	AppleOfficial
+)
+
+// healthModelNames is indexed by HealthModel.
+var healthModelNames = []string{
+	ByMaxCapacity:     "max-capacity",
+	ByNominalCapacity: "nominal-capacity",
+	ConditionAdjusted: "condition-adjusted",
+	AppleOfficial:     "apple-official",
+}
+
+// String returns a lower-case name for the model, such as "max-capacity".
+func (m HealthModel) String() string {
+	if m < 0 || int(m) >= len(healthModelNames) {
+		return "unknown"
+	}
+	return healthModelNames[m]
+}
+
+// MarshalText implements encoding.TextMarshaler using the String names.
+func (m HealthModel) MarshalText() ([]byte, error) {
+	return enumText(m, healthModelNames)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (m *HealthModel) UnmarshalText(text []byte) error {
+	v, err := parseEnumText[HealthModel](text, healthModelNames)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Health returns the battery health percentage under the chosen model, so
+// callers state which definition they trust instead of picking a
+// Calculations field by name. Unknown models return 0.
+//
+// AppleOfficial is SystemSettingsHealthEstimate.
+func (b *BatteryInfo) Health(model HealthModel) int {
+	switch model {
+	case ByMaxCapacity:
+		return b.Calculations.HealthByMaxCapacity
+	case ByNominalCapacity:
+		return b.Calculations.HealthByNominalCapacity
+	case ConditionAdjusted:
+		return b.Calculations.ConditionAdjustedHealth
+	case AppleOfficial:
+		return b.SystemSettingsHealthEstimate()
+	}
+	return 0
+}
+
+// SystemSettingsHealthEstimate estimates the "Maximum Capacity" percentage
+// that System Settings shows for the battery. It is our best
+// reverse-engineered match, not Apple's formula, so expect an occasional
+// difference of a point: the nominal full-charge capacity against the design
+// capacity, rounded to a whole percent and never more than 100%. System
+// Settings uses the nominal capacity because it moves less than MaxCapacity
+// between charges, which is why HealthByMaxCapacity often reads a few points
+// differently. When NominalCapacity isn't reported, MaxCapacity stands in.
+// It returns 0 if the design capacity is unknown.
+func (b *BatteryInfo) SystemSettingsHealthEstimate() int {
+	health := b.Calculations.HealthByNominalCapacityFloat
+	if b.Battery.NominalCapacity <= 0 {
+		health = b.Calculations.HealthByMaxCapacityFloat
+	}
+	return max(min(int(math.Round(health)), 100), 0)
+}
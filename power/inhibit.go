@@ -0,0 +1,90 @@
+package power
+
+// InhibitReason explains why a connected battery isn't charging.
+type InhibitReason int
+
+const (
+	// NotInhibited means the battery is charging, is full, or is not on
+	// external power.
+	NotInhibited InhibitReason = iota
+
+	// InhibitOptimizedCharging means Optimized Battery Charging is holding
+	// the charge until it expects the machine to be unplugged.
+	InhibitOptimizedCharging
+
+	// InhibitCalibration means the gauge is holding off charging to
+	// recalibrate (see State.CalibrationInProgress).
+	InhibitCalibration
+
+	// InhibitThermal means the battery is too warm to charge at full rate.
+	InhibitThermal
+
+	// InhibitOther means charging is held back for a reason the package
+	// can't identify; Charger.NotChargingReason has the raw bits.
+	InhibitOther
+)
+
+// inhibitReasonNames is indexed by InhibitReason.
+var inhibitReasonNames = []string{
+	NotInhibited:             "none",
+	InhibitOptimizedCharging: "optimized-charging",
+	InhibitCalibration:       "calibration",
+	InhibitThermal:           "thermal",
+	InhibitOther:             "other",
+}
+
+// String returns a lower-case name for the reason, such as "thermal".
+func (r InhibitReason) String() string {
+	if r < 0 || int(r) >= len(inhibitReasonNames) {
+		return "unknown"
+	}
+	return inhibitReasonNames[r]
+}
+
+// MarshalText implements encoding.TextMarshaler using the String names.
+func (r InhibitReason) MarshalText() ([]byte, error) {
+	return enumText(r, inhibitReasonNames)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *InhibitReason) UnmarshalText(text []byte) error {
+	v, err := parseEnumText[InhibitReason](text, inhibitReasonNames)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+// ChargeInhibitReason explains why the battery isn't charging while on
+// external power, using the default temperature threshold. See
+// ChargeInhibitReasonWithOptions.
+func (b *BatteryInfo) ChargeInhibitReason() InhibitReason {
+	return b.ChargeInhibitReasonWithOptions(Options{})
+}
+
+// ChargeInhibitReasonWithOptions explains why the battery isn't charging, or
+// is charging slower than it could, while on external power. Optimized
+// charging and calibration are checked first, since both stop charging
+// outright; a battery above opts.ThermalThrottleCelsius that is held back or
+// throttled (see IsThermallyThrottledWithOptions) is InhibitThermal. Any other
+// stop short of full, or a non-zero Charger.NotChargingReason, is
+// InhibitOther.
+func (b *BatteryInfo) ChargeInhibitReasonWithOptions(opts Options) InhibitReason {
+	if !b.State.IsConnected || b.State.FullyCharged {
+		return NotInhibited
+	}
+	stopped := !b.State.IsCharging
+	switch {
+	case stopped && b.State.OptimizedChargingActive:
+		return InhibitOptimizedCharging
+	case stopped && b.State.CalibrationInProgress:
+		return InhibitCalibration
+	case stopped && b.Battery.Temperature > opts.thermalThrottleCelsius(),
+		b.IsThermallyThrottledWithOptions(opts):
+		return InhibitThermal
+	case stopped, b.Charger.NotChargingReason != 0:
+		return InhibitOther
+	}
+	return NotInhibited
+}
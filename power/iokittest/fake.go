@@ -0,0 +1,77 @@
+// Package iokittest provides canned power telemetry for examples, docs and
+// tests that need to run without a Mac or a battery.
+package iokittest
+
+import (
+	"time"
+
+	"github.com/peterneutron/go-iokit-powertelemetry/power"
+)
+
+// FakeBatteryInfo returns a realistic, fully populated snapshot of a
+// three-cell MacBook battery charging from a 96W USB-C PD adapter, with its
+// Calculations derived through the same path as a live read. Every call
+// returns an identical, independent value, so it is safe to modify.
+func FakeBatteryInfo() *power.BatteryInfo {
+	info := &power.BatteryInfo{
+		ReadAt: time.Date(2024, time.January, 2, 15, 4, 5, 0, time.UTC),
+		State: power.State{
+			IsCharging:       true,
+			IsConnected:      true,
+			BatteryInstalled: true,
+		},
+		Battery: power.Battery{
+			SerialNumber:            "F5D0000000000000A",
+			DeviceName:              "bq40z651",
+			FirmwareVersion:         "1.1",
+			ChemID:                  28524,
+			CycleCount:              182,
+			GaugeStatus:             0x00e0,
+			DesignCapacity:          6075,
+			MaxCapacity:             5612,
+			NominalCapacity:         5720,
+			CurrentCapacity:         3871,
+			TimeToEmpty:             65535,
+			TimeToFull:              54,
+			Temperature:             30.71,
+			Voltage:                 12.437,
+			Amperage:                2.104,
+			IndividualCellVoltages:  []int{4144, 4147, 4146},
+			TemperatureCentidegrees: 3071,
+			MaxChargeCurrent:        4.312,
+			MaxDischargeCurrent:     6.218,
+			BatteryDataVoltage:      12.441,
+			VoltageMV:               12437,
+			AmperageMA:              2104,
+		},
+		Adapter: power.Adapter{
+			Description:        "pd charger",
+			MaxWatts:           96,
+			MaxVoltage:         20.0,
+			MaxAmperage:        4.7,
+			CableCurrentRating: 5.0,
+			InputVoltage:       19.82,
+			InputAmperage:      2.14,
+			Role:               power.RoleSink,
+		},
+		Charger: power.Charger{
+			ChargingVoltage: 13.05,
+			ChargingCurrent: 2.2,
+			VacVoltageLimit: 20.4,
+		},
+		Capabilities: map[power.Capability]bool{
+			power.CapAdapterCableCurrentRating: true,
+			power.CapAdapterTemperature:        false,
+			power.CapAdapterIsWireless:         false,
+			power.CapOptimizedCharging:         true,
+			power.CapGaugeStatus:               true,
+			power.CapChargerData:               true,
+			power.CapMaxChargeCurrent:          true,
+			power.CapMaxDischargeCurrent:       true,
+			power.CapTimeSinceFullCharge:       false,
+			power.CapBatteryDataVoltage:        true,
+		},
+	}
+	info.Recalculate(power.Options{})
+	return info
+}
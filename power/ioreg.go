@@ -0,0 +1,374 @@
+package power
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errNoIORegBattery is returned by ParseIORegDump when the dump has no
+// AppleSmartBattery entry.
+var errNoIORegBattery = errors.New("power: no " + ServiceAppleSmartBattery + " entry in ioreg dump")
+
+// maxCellVoltages is how many cell voltages a snapshot holds, as MAX_CELLS
+// caps them for GetBatteryInfo; see Battery.CellVoltagesTruncated.
+const maxCellVoltages = 16
+
+// ParseIORegDump builds a snapshot from the AppleSmartBattery entry of a
+// textual I/O Registry dump, such as the output of `ioreg -l` or
+// `ioreg -rn AppleSmartBattery`, so that a battery can be analysed away from
+// the machine it is in. The same keys are read as by GetBatteryInfo and the
+// Calculations are derived as by Recalculate with the zero Options. Cell
+// voltages are capped at the same count as a live read, setting
+// Battery.CellVoltagesTruncated.
+//
+// Flags that GetBatteryInfo also reads from the AppleSmartBatteryManager
+// parent are only taken from the battery entry itself, and ReadAt and
+// ReadLatency are left zero, since the dump doesn't say when it was taken.
+// Properties whose values can't be parsed are skipped.
+func ParseIORegDump(r io.Reader) (*BatteryInfo, error) {
+	props, err := findIORegEntry(r, ServiceAppleSmartBattery)
+	if err != nil {
+		return nil, err
+	}
+	info := batteryInfoFromProperties(props)
+	calculateDerivedMetrics(info, Options{})
+	return info, nil
+}
+
+// findIORegEntry returns the properties of the first entry of the given class
+// in a textual ioreg dump.
+func findIORegEntry(r io.Reader, class string) (ioregDict, error) {
+	scanner := bufio.NewScanner(r)
+	// BatteryData alone can run to several kilobytes on one line.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var props ioregDict
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " |")
+		switch {
+		case props == nil:
+			if strings.Contains(line, "<class "+class+",") {
+				props = ioregDict{}
+			}
+		case line == "{" || line == "":
+		case line == "}" || strings.HasPrefix(line, "+-o "):
+			return props, nil
+		default:
+			if key, value, err := parseIORegProperty(line); err == nil {
+				props[key] = value
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if props == nil {
+		return nil, errNoIORegBattery
+	}
+	return props, nil
+}
+
+// parseIORegProperty parses a `"Key" = value` property line.
+func parseIORegProperty(line string) (key string, value any, err error) {
+	p := &ioregParser{s: line}
+	k, err := p.value()
+	if err != nil {
+		return "", nil, err
+	}
+	key, ok := k.(string)
+	if !ok || !p.consume('=') {
+		return "", nil, fmt.Errorf("power: ioreg malformed property %q", line)
+	}
+	if value, err = p.value(); err != nil {
+		return "", nil, err
+	}
+	return key, value, nil
+}
+
+// ioregParser decodes the value syntax ioreg prints: "strings", Yes and No,
+// integers, <hex data>, {"key"=value,...} dictionaries and (value,...)
+// arrays. Integers are int64; ioreg prints negative values as their unsigned
+// 64-bit form, which wraps back to the negative value.
+type ioregParser struct {
+	s   string
+	pos int
+}
+
+func (p *ioregParser) skipSpace() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// consume skips spaces and then c, reporting whether c was there.
+func (p *ioregParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// value parses the value at the current position.
+func (p *ioregParser) value() (any, error) {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return nil, errors.New("power: ioreg unexpected end of value")
+	}
+	switch p.s[p.pos] {
+	case '"':
+		end := strings.IndexByte(p.s[p.pos+1:], '"')
+		if end < 0 {
+			return nil, errors.New("power: ioreg unterminated string")
+		}
+		s := p.s[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return s, nil
+	case '<':
+		end := strings.IndexByte(p.s[p.pos:], '>')
+		if end < 0 {
+			return nil, errors.New("power: ioreg unterminated data")
+		}
+		text := p.s[p.pos+1 : p.pos+end]
+		p.pos += end + 1
+		if data, err := hex.DecodeString(text); err == nil {
+			return data, nil
+		}
+		return text, nil
+	case '{':
+		p.pos++
+		dict := ioregDict{}
+		for !p.consume('}') {
+			k, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := k.(string)
+			if !ok || !p.consume('=') {
+				return nil, errors.New("power: ioreg malformed dictionary")
+			}
+			if dict[key], err = p.value(); err != nil {
+				return nil, err
+			}
+			p.consume(',')
+		}
+		return dict, nil
+	case '(':
+		p.pos++
+		array := []any{}
+		for !p.consume(')') {
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, v)
+			p.consume(',')
+		}
+		return array, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) && !strings.ContainsRune(",})", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	token := strings.TrimSpace(p.s[start:p.pos])
+	switch token {
+	case "":
+		return nil, errors.New("power: ioreg empty value")
+	case "Yes":
+		return true, nil
+	case "No":
+		return false, nil
+	}
+	if i, err := strconv.ParseInt(token, 0, 64); err == nil {
+		return i, nil
+	}
+	if u, err := strconv.ParseUint(token, 0, 64); err == nil {
+		return int64(u), nil
+	}
+	if f, err := strconv.ParseFloat(token, 64); err == nil {
+		return f, nil
+	}
+	return token, nil
+}
+
+// ioregDict is a parsed ioreg dictionary. Its getters mirror the C helpers in
+// cfhelpers.h: a missing key or a value of the wrong type reads as zero.
+type ioregDict map[string]any
+
+func (d ioregDict) has(key string) bool {
+	_, ok := d[key]
+	return ok
+}
+
+func (d ioregDict) long(key string) int64 {
+	switch v := d[key].(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+func (d ioregDict) bool(key string) bool {
+	switch v := d[key].(type) {
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	}
+	return false
+}
+
+func (d ioregDict) string(key string) string {
+	s, _ := d[key].(string)
+	return s
+}
+
+func (d ioregDict) dict(key string) ioregDict {
+	dict, _ := d[key].(ioregDict)
+	return dict
+}
+
+func (d ioregDict) array(key string) []any {
+	array, _ := d[key].([]any)
+	return array
+}
+
+// batteryInfoFromProperties fills the raw fields of a snapshot from the
+// AppleSmartBattery properties, as get_battery_info does from the live
+// registry entry.
+func batteryInfoFromProperties(props ioregDict) *BatteryInfo {
+	batteryData := props.dict(KeyBatteryData)
+	lifetimeData := batteryData.dict(KeyLifetimeData)
+	adapterDetails := props.dict(KeyAdapterDetails)
+	powerTelemetry := props.dict(KeyPowerTelemetryData)
+	chargerData := props.dict(KeyChargerData)
+
+	maxCapacityKey := KeyAppleRawMaxCapacity
+	if !props.has(maxCapacityKey) {
+		maxCapacityKey = KeyMaxCapacity
+	}
+	optimizedCharging := props
+	if !props.has(KeyOptimizedBatteryChargingEngaged) {
+		optimizedCharging = chargerData
+	}
+	chemID := props.long(KeyChemID)
+	if chemID == 0 {
+		chemID = batteryData.long(KeyChemID)
+	}
+	temperature := props.long(KeyTemperature)
+	voltage := props.long(KeyVoltage)
+	amperage := props.long(KeyAmperage)
+
+	info := &BatteryInfo{
+		State: State{
+			IsCharging:              props.bool(KeyIsCharging),
+			IsConnected:             props.bool(KeyExternalConnected),
+			FullyCharged:            props.bool(KeyFullyCharged),
+			OptimizedChargingActive: optimizedCharging.bool(KeyOptimizedBatteryChargingEngaged),
+			BatteryInstalled:        props.bool(KeyBatteryInstalled),
+			AtCriticalLevel:         props.bool(KeyAtCriticalLevel),
+		},
+		Battery: Battery{
+			SerialNumber:            props.string(KeySerial),
+			DeviceName:              props.string(KeyDeviceName),
+			FirmwareVersion:         props.string(KeyFirmwareVersion),
+			Manufacturer:            props.string(KeyManufacturer),
+			ChemID:                  int(chemID),
+			CycleCount:              int(props.long(KeyCycleCount)),
+			PermanentFailureStatus:  int(props.long(KeyPermanentFailureStatus)),
+			GaugeStatus:             int(batteryData.long(KeyGaugeFlagRaw)),
+			CalibrationNeeded:       batteryData.long(KeyGaugeFlagRaw)&gaugeConditionFlag != 0,
+			DesignCapacity:          int(props.long(KeyDesignCapacity)),
+			MaxCapacity:             int(props.long(maxCapacityKey)),
+			NominalCapacity:         int(props.long(KeyNominalChargeCapacity)),
+			CurrentCapacity:         int(props.long(KeyAppleRawCurrentCapacity)),
+			TimeToEmpty:             int(props.long(KeyAvgTimeToEmpty)),
+			TimeToFull:              int(props.long(KeyAvgTimeToFull)),
+			Temperature:             float64(temperature) / 100.0,
+			Voltage:                 float64(voltage) / 1000.0,
+			Amperage:                float64(amperage) / 1000.0,
+			TemperatureCentidegrees: int(temperature),
+			MaxChargeCurrent:        float64(lifetimeData.long(KeyMaximumChargeCurrent)) / 1000.0,
+			BatteryDataVoltage:      float64(batteryData.long(KeyVoltage)) / 1000.0,
+			MaxDischargeCurrent:     math.Abs(float64(lifetimeData.long(KeyMaximumDischargeCurrent)) / 1000.0),
+			VoltageMV:               int(voltage),
+			AmperageMA:              int(amperage),
+			TimeSinceFullCharge:     time.Duration(props.long(KeyTimeSinceLastFullCharge)) * time.Second,
+		},
+		Adapter: Adapter{
+			Description:        adapterDetails.string(KeyDescription),
+			MaxWatts:           int(adapterDetails.long(KeyWatts)),
+			MaxVoltage:         float64(adapterDetails.long(KeyAdapterVoltage)) / 1000.0,
+			MaxAmperage:        float64(adapterDetails.long(KeyCurrent)) / 1000.0,
+			CableCurrentRating: float64(adapterDetails.long(KeyCableCurrent)) / 1000.0,
+			Temperature:        float64(adapterDetails.long(KeyAdapterTemperature)) / 100.0,
+			InputVoltage:       float64(powerTelemetry.long(KeySystemVoltageIn)) / 1000.0,
+			InputAmperage:      float64(powerTelemetry.long(KeySystemCurrentIn)) / 1000.0,
+			IsWireless:         adapterDetails.bool(KeyIsWireless),
+			Role:               adapterRole(props.bool(KeyExternalConnected)),
+			PDRevision:         adapterDetails.string(KeyPDRevision),
+			SupportsPPS:        adapterDetails.bool(KeySupportsPPS),
+		},
+		Charger: Charger{
+			ChargingVoltage:   float64(chargerData.long(KeyChargingVoltage)) / 1000.0,
+			ChargingCurrent:   float64(chargerData.long(KeyChargingCurrent)) / 1000.0,
+			NotChargingReason: int(chargerData.long(KeyNotChargingReason)),
+			VacVoltageLimit:   float64(chargerData.long(KeyVacVoltageLimit)) / 1000.0,
+		},
+		Capabilities: map[Capability]bool{
+			CapAdapterCableCurrentRating: adapterDetails.has(KeyCableCurrent),
+			CapAdapterTemperature:        adapterDetails.has(KeyAdapterTemperature),
+			CapOptimizedCharging:         optimizedCharging.has(KeyOptimizedBatteryChargingEngaged),
+			CapGaugeStatus:               batteryData.has(KeyGaugeFlagRaw),
+			CapChargerData:               chargerData != nil,
+			CapMaxChargeCurrent:          lifetimeData.has(KeyMaximumChargeCurrent),
+			CapMaxDischargeCurrent:       lifetimeData.has(KeyMaximumDischargeCurrent),
+			CapTimeSinceFullCharge:       props.has(KeyTimeSinceLastFullCharge),
+			CapAdapterIsWireless:         adapterDetails.has(KeyIsWireless),
+			CapAdapterPDRevision:         adapterDetails.string(KeyPDRevision) != "",
+			CapAdapterPPS:                adapterDetails.has(KeySupportsPPS),
+			CapBatteryDataVoltage:        batteryData.has(KeyVoltage),
+		},
+	}
+
+	// Older gauges only report a numeric firmware revision.
+	if info.Battery.FirmwareVersion == "" && props.long(KeyGasGaugeFirmwareVersion) != 0 {
+		info.Battery.FirmwareVersion = fmt.Sprintf("%d", props.long(KeyGasGaugeFirmwareVersion))
+	}
+
+	for _, v := range props.array(KeyAppleRawAdapterDetails) {
+		details, ok := v.(ioregDict)
+		if !ok {
+			continue
+		}
+		info.RecentAdapters = append(info.RecentAdapters, Adapter{
+			Description: details.string(KeyDescription),
+			MaxWatts:    int(details.long(KeyWatts)),
+			MaxVoltage:  float64(details.long(KeyAdapterVoltage)) / 1000.0,
+			MaxAmperage: float64(details.long(KeyCurrent)) / 1000.0,
+			IsWireless:  details.bool(KeyIsWireless),
+		})
+	}
+
+	for _, v := range batteryData.array(KeyCellVoltage) {
+		if mv, ok := v.(int64); ok {
+			info.Battery.IndividualCellVoltages = append(info.Battery.IndividualCellVoltages, int(mv))
+		}
+	}
+	if cells := info.Battery.IndividualCellVoltages; len(cells) > maxCellVoltages {
+		info.Battery.IndividualCellVoltages = cells[:maxCellVoltages]
+		info.Battery.CellVoltagesTruncated = true
+	}
+	return info
+}
@@ -0,0 +1,43 @@
+package power
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseIORegDumpCellVoltages(t *testing.T) {
+	tests := []struct {
+		name      string
+		cells     int
+		truncated bool
+	}{
+		{"three cells", 3, false},
+		{"at the cap", maxCellVoltages, false},
+		{"over the cap", maxCellVoltages + 4, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mv := make([]string, tt.cells)
+			for i := range mv {
+				mv[i] = fmt.Sprint(4000 + i)
+			}
+			dump := "+-o AppleSmartBattery  <class AppleSmartBattery, id 0x100000456, registered, matched, active, busy 0 (0 ms), retain 8>\n" +
+				"    {\n" +
+				`      "BatteryData" = {"CellVoltage"=(` + strings.Join(mv, ",") + ")}\n" +
+				"    }\n"
+
+			info, err := ParseIORegDump(strings.NewReader(dump))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := min(tt.cells, maxCellVoltages)
+			if got := len(info.Battery.IndividualCellVoltages); got != want {
+				t.Errorf("len(IndividualCellVoltages) = %d, want %d", got, want)
+			}
+			if info.Battery.CellVoltagesTruncated != tt.truncated {
+				t.Errorf("CellVoltagesTruncated = %v, want %v", info.Battery.CellVoltagesTruncated, tt.truncated)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package power
+
+// ServiceAppleSmartBattery is the I/O Registry class this package matches to
+// find the battery.
+const ServiceAppleSmartBattery = "AppleSmartBattery"
+
+// IOKit property keys read from the AppleSmartBattery service, for
+// comparison with `ioreg -rn AppleSmartBattery` and for tooling that reads
+// further keys alongside this package. The C code in telemetry_darwin.go
+// uses the same literals, and ParseIORegDump reads these constants; keep
+// both in sync when adding a key.
+const (
+	// Top-level keys.
+	KeyIsCharging                      = "IsCharging"
+	KeyExternalConnected               = "ExternalConnected"
+	KeyFullyCharged                    = "FullyCharged"
+	KeyBatteryInstalled                = "BatteryInstalled" // also on AppleSmartBatteryManager
+	KeyAtCriticalLevel                 = "AtCriticalLevel"  // also on AppleSmartBatteryManager
+	KeyOptimizedBatteryChargingEngaged = "OptimizedBatteryChargingEngaged"
+	KeyCycleCount                      = "CycleCount"
+	KeyPermanentFailureStatus          = "PermanentFailureStatus"
+	KeyDesignCapacity                  = "DesignCapacity"
+	KeyAppleRawMaxCapacity             = "AppleRawMaxCapacity"
+	KeyMaxCapacity                     = "MaxCapacity" // fallback when AppleRawMaxCapacity is absent
+	KeyNominalChargeCapacity           = "NominalChargeCapacity"
+	KeyAppleRawCurrentCapacity         = "AppleRawCurrentCapacity"
+	KeyAvgTimeToEmpty                  = "AvgTimeToEmpty"
+	KeyAvgTimeToFull                   = "AvgTimeToFull"
+	KeyTimeSinceLastFullCharge         = "TimeSinceLastFullCharge"
+	KeyTemperature                     = "Temperature"
+	KeyVoltage                         = "Voltage" // also in BatteryData
+	KeyAmperage                        = "Amperage"
+	KeySerial                          = "Serial"
+	KeyDeviceName                      = "DeviceName"
+	KeyFirmwareVersion                 = "FirmwareVersion"
+	KeyGasGaugeFirmwareVersion         = "GasGaugeFirmwareVersion"
+	KeyManufacturer                    = "Manufacturer"
+	KeyChemID                          = "ChemID" // also in BatteryData
+
+	// Nested dictionaries.
+	KeyBatteryData        = "BatteryData"
+	KeyLifetimeData       = "LifetimeData" // inside BatteryData
+	KeyAdapterDetails     = "AdapterDetails"
+	KeyPowerTelemetryData = "PowerTelemetryData"
+	KeyChargerData        = "ChargerData"
+
+	// KeyAppleRawAdapterDetails is an array of AdapterDetails-style
+	// dictionaries for recently connected adapters.
+	KeyAppleRawAdapterDetails = "AppleRawAdapterDetails"
+
+	// BatteryData keys.
+	KeyCellVoltage  = "CellVoltage"
+	KeyGaugeFlagRaw = "GaugeFlagRaw"
+
+	// LifetimeData keys.
+	KeyMaximumChargeCurrent    = "MaximumChargeCurrent"
+	KeyMaximumDischargeCurrent = "MaximumDischargeCurrent"
+
+	// AdapterDetails keys.
+	KeyWatts              = "Watts"
+	KeyAdapterVoltage     = "AdapterVoltage"
+	KeyCurrent            = "Current"
+	KeyCableCurrent       = "CableCurrent"
+	KeyAdapterTemperature = "AdapterTemperature"
+	KeyDescription        = "Description"
+	KeyIsWireless         = "IsWireless"
+	KeyPDRevision         = "PDRevision"
+	KeySupportsPPS        = "SupportsPPS"
+
+	// PowerTelemetryData keys.
+	KeySystemVoltageIn = "SystemVoltageIn"
+	KeySystemCurrentIn = "SystemCurrentIn"
+
+	// ChargerData keys.
+	KeyChargingVoltage   = "ChargingVoltage"
+	KeyChargingCurrent   = "ChargingCurrent"
+	KeyNotChargingReason = "NotChargingReason"
+	KeyVacVoltageLimit   = "VacVoltageLimit"
+)
@@ -0,0 +1,38 @@
+//go:build darwin && cgo
+
+package power
+
+import (
+	"errors"
+	"testing"
+)
+
+// leakReads is how many reads the leak test performs between samples. A
+// reference leaked per read shows up as a difference of leakReads.
+const leakReads = 1000
+
+// TestReadsKeepRetainCounts checks that reads release every reference they
+// take: the user references held on the battery service and the retain
+// counts of a properties dictionary and its nested BatteryData must be the
+// same after many reads as before.
+func TestReadsKeepRetainCounts(t *testing.T) {
+	probe, err := newRetainProbe()
+	if errors.Is(err, ErrServiceVanished) {
+		t.Skip("no AppleSmartBattery service on this machine")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer probe.close()
+
+	before := probe.counts()
+	for i := 0; i < leakReads; i++ {
+		if _, err := GetBatteryInfo(); err != nil {
+			t.Fatal(err)
+		}
+		probe.decode()
+	}
+	if after := probe.counts(); after != before {
+		t.Errorf("retain counts changed over %d reads: before %+v, after %+v", leakReads, before, after)
+	}
+}
@@ -0,0 +1,152 @@
+package power
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// logFilePrefix and logFileSuffix frame the names of the files that
+	// LogToRotatingFile writes, e.g. battery-20261014T170000Z.jsonl. Only
+	// files named this way are ever removed.
+	logFilePrefix = "battery-"
+	logFileSuffix = ".jsonl"
+
+	// logFileTimeFormat is the UTC start of a file's window in its name. It
+	// sorts lexically in time order.
+	logFileTimeFormat = "20060102T150405Z"
+
+	// logFilesKept is how many log files LogToRotatingFile keeps, including
+	// the one being written; older ones are deleted on rotation.
+	logFilesKept = 48
+
+	// defaultLogInterval and defaultLogRotate apply when LogToRotatingFile
+	// is given a non-positive interval or rotation period.
+	defaultLogInterval = time.Minute
+	defaultLogRotate   = 24 * time.Hour
+)
+
+// LogToRotatingFile samples the battery every interval and appends each
+// snapshot to a JSON-lines file in dir, one JSON object per line, turning the
+// package into a standalone logging daemon. It blocks until ctx is done and
+// then returns nil.
+//
+// A new file is started every rotate, named after the UTC start of its
+// window (battery-20261014T170000Z.jsonl) with windows aligned to multiples
+// of rotate, so a restarted logger appends to the current window's file. On
+// each rotation only the newest 48 log files are kept and older ones are
+// deleted; other files in dir are never touched. dir is created if needed.
+// A non-positive interval means one minute and a non-positive rotate one
+// day.
+//
+// Read failures are skipped, as with AlarmBelow, except ErrUnsupported,
+// which is returned. File errors are returned immediately.
+func LogToRotatingFile(ctx context.Context, dir string, interval, rotate time.Duration) error {
+	if interval <= 0 {
+		interval = defaultLogInterval
+	}
+	if rotate <= 0 {
+		rotate = defaultLogRotate
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	snapshots, errs := Watch(ctx, interval)
+
+	var (
+		file   *os.File
+		window time.Time
+	)
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	for {
+		select {
+		case err, ok := <-errs:
+			if ok && errors.Is(err, ErrUnsupported) {
+				return err
+			}
+			if !ok {
+				errs = nil
+			}
+		case info, ok := <-snapshots:
+			if !ok {
+				// Watch closes both channels together; an ErrUnsupported
+				// may still be waiting in errs.
+				if errs != nil {
+					for err := range errs {
+						if errors.Is(err, ErrUnsupported) {
+							return err
+						}
+					}
+				}
+				return nil
+			}
+			if start := info.ReadAt.UTC().Truncate(rotate); file == nil || !start.Equal(window) {
+				if file != nil {
+					if err := file.Close(); err != nil {
+						file = nil
+						return err
+					}
+				}
+				var err error
+				if file, err = openLogFile(dir, start); err != nil {
+					return err
+				}
+				window = start
+				if err := pruneLogFiles(dir, logFilesKept); err != nil {
+					return err
+				}
+			}
+			line, err := json.Marshal(info)
+			if err != nil {
+				return err
+			}
+			if _, err := file.Write(append(line, '\n')); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// openLogFile opens the log file for the window starting at start for
+// appending, creating it if needed.
+func openLogFile(dir string, start time.Time) (*os.File, error) {
+	name := logFilePrefix + start.Format(logFileTimeFormat) + logFileSuffix
+	return os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+}
+
+// pruneLogFiles deletes all but the newest keep log files in dir.
+func pruneLogFiles(dir string, keep int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, logFilePrefix) && strings.HasSuffix(name, logFileSuffix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for len(names) > keep {
+		if err := os.Remove(filepath.Join(dir, names[0])); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
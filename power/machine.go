@@ -0,0 +1,51 @@
+package power
+
+import "strings"
+
+// HardwareModel returns the machine's model identifier as reported by
+// `sysctl hw.model` (e.g., "MacBookPro18,3" or "Mac14,2").
+func HardwareModel() (string, error) {
+	return sysctlString("hw.model")
+}
+
+// MachineHasBatterySupport reports whether this Mac is a portable designed to
+// have a battery. Use it to tell a desktop, which never has a battery, from a
+// portable whose battery read failed or is missing.
+//
+// Older model identifiers name the family ("MacBookPro18,3"); newer ones do
+// not ("Mac14,2" is a MacBook Air, "Mac14,3" a Mac mini), so the platform's
+// marketing product name is consulted as well.
+func MachineHasBatterySupport() bool {
+	if model, err := HardwareModel(); err == nil && strings.HasPrefix(model, "MacBook") {
+		return true
+	}
+	return strings.Contains(platformProductName(), "MacBook")
+}
+
+// PowerSinceBoot estimates the machine's average power draw since boot, in
+// Watts, as the battery energy used since boot divided by the uptime from
+// `sysctl kern.boottime`.
+//
+// The package keeps no history, so the energy used is taken to be the charge
+// missing from a full battery, (MaxCapacity − CurrentCapacity) × Voltage. The
+// figure is therefore only accurate for a machine that booted fully charged
+// and has run on battery since. Energy drawn from the adapter, and any
+// charging in between, is not counted, so on a machine that spends time
+// plugged in it underestimates. For a measured average, sample with an
+// EnergyCounter instead.
+func PowerSinceBoot() (avgWatts float64, err error) {
+	booted, err := bootTime()
+	if err != nil {
+		return 0, err
+	}
+	info, err := GetBatteryInfo()
+	if err != nil {
+		return 0, err
+	}
+	uptime := info.ReadAt.Sub(booted)
+	if uptime <= 0 {
+		return 0, nil
+	}
+	usedWh := float64(info.Battery.MaxCapacity-info.Battery.CurrentCapacity) * info.Battery.Voltage / 1000.0
+	return finite(max(usedWh, 0) / uptime.Hours()), nil
+}
@@ -0,0 +1,95 @@
+package power
+
+/*
+#cgo LDFLAGS: -framework CoreFoundation -framework IOKit
+
+#include <stdlib.h>
+#include <string.h>
+#include <sys/time.h>
+#include <sys/sysctl.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/IOKitLib.h>
+
+// Reads a string sysctl such as "hw.model". Returns 0 on success.
+static int sysctl_string(const char *name, char *buffer, size_t size) {
+    size_t len = size;
+    if (sysctlbyname(name, buffer, &len, NULL, 0) != 0) {
+        buffer[0] = '\0';
+        return -1;
+    }
+    buffer[size - 1] = '\0';
+    return 0;
+}
+
+// Reads kern.boottime as seconds since the epoch. Returns 0 on success.
+static int boot_time(long long *sec, int *usec) {
+    struct timeval tv;
+    size_t len = sizeof(tv);
+    if (sysctlbyname("kern.boottime", &tv, &len, NULL, 0) != 0) return -1;
+    *sec = tv.tv_sec;
+    *usec = tv.tv_usec;
+    return 0;
+}
+
+// Reads the marketing product name (e.g., "MacBook Air (M2, 2022)") from the
+// platform expert. Apple Silicon stores it as CFData, Intel as a CFString.
+// Leaves an empty string if it isn't available.
+static void get_platform_product_name(char *buffer, int buffer_size) {
+    buffer[0] = '\0';
+
+    // IOServiceGetMatchingService consumes the matching dictionary.
+    io_service_t platform = IOServiceGetMatchingService(kIOMainPortDefault, IOServiceMatching("IOPlatformExpertDevice"));
+    if (platform == IO_OBJECT_NULL) return;
+
+    CFTypeRef value = IORegistryEntryCreateCFProperty(platform, CFSTR("product-name"), kCFAllocatorDefault, 0);
+    IOObjectRelease(platform);
+    if (value == NULL) return;
+
+    if (CFGetTypeID(value) == CFStringGetTypeID()) {
+        CFStringGetCString((CFStringRef)value, buffer, buffer_size, kCFStringEncodingUTF8);
+    } else if (CFGetTypeID(value) == CFDataGetTypeID()) {
+        CFIndex len = CFDataGetLength((CFDataRef)value);
+        if (len >= buffer_size) len = buffer_size - 1;
+        memcpy(buffer, CFDataGetBytePtr((CFDataRef)value), len);
+        buffer[len] = '\0';
+    }
+    CFRelease(value);
+}
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// sysctlString reads a string-valued sysctl.
+func sysctlString(name string) (string, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	var buf [256]C.char
+	if C.sysctl_string(cName, &buf[0], C.size_t(len(buf))) != 0 {
+		return "", fmt.Errorf("power: sysctl %s failed", name)
+	}
+	return C.GoString(&buf[0]), nil
+}
+
+// bootTime returns when the kernel booted, from kern.boottime.
+func bootTime() (time.Time, error) {
+	var sec C.longlong
+	var usec C.int
+	if C.boot_time(&sec, &usec) != 0 {
+		return time.Time{}, errors.New("power: sysctl kern.boottime failed")
+	}
+	return time.Unix(int64(sec), int64(usec)*int64(time.Microsecond)), nil
+}
+
+// platformProductName returns the machine's marketing name, or "" if IOKit
+// doesn't report one.
+func platformProductName() string {
+	var buf [256]C.char
+	C.get_platform_product_name(&buf[0], C.int(len(buf)))
+	return C.GoString(&buf[0])
+}
@@ -0,0 +1,14 @@
+package power
+
+import "encoding/json"
+
+// MarshalJSONStable encodes the snapshot as JSON that is byte-for-byte
+// identical for identical data, so outputs can be diffed across runs. Struct
+// fields keep their declaration order and every map-valued field, such as
+// Capabilities, is written with its keys sorted.
+func (b *BatteryInfo) MarshalJSONStable() ([]byte, error) {
+	// encoding/json already emits struct fields in declaration order and
+	// sorts map keys; this method pins that behaviour as part of the API so
+	// that future map-valued fields are covered too.
+	return json.Marshal(b)
+}
@@ -0,0 +1,96 @@
+package power
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Metrics returns every numeric data point of the snapshot as a flat map, for
+// generic metric exporters that shouldn't need to know the struct shape.
+//
+// Keys are the snake_case field path, in lower case: a section and a field,
+// such as "battery.voltage", "adapter.max_watts" or
+// "calculations.ac_power", or a bare field for the top level, such as
+// "read_latency". Values keep the field's units (see the field docs).
+// Booleans are 0 or 1, durations are in seconds, and each
+// IndividualCellVoltages entry gets a 1-based key such as
+// "battery.individual_cell_voltages.1". Strings, times, maps and
+// RecentAdapters are left out. A nil snapshot has no metrics.
+//
+// The keys are derived from the exported field names, so they are as stable
+// as the fields themselves, and new numeric fields appear automatically.
+func (b *BatteryInfo) Metrics() map[string]float64 {
+	if b == nil {
+		return nil
+	}
+	metrics := map[string]float64{}
+	addMetrics(metrics, "", reflect.ValueOf(b).Elem())
+	return metrics
+}
+
+// addMetrics adds the numeric fields of struct v to metrics under prefix.
+func addMetrics(metrics map[string]float64, prefix string, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := prefix + snakeCase(field.Name)
+		fv := v.Field(i)
+		switch {
+		case fv.Type() == timeType:
+		case fv.Type() == durationType:
+			metrics[key] = time.Duration(fv.Int()).Seconds()
+		case fv.Kind() == reflect.Struct:
+			addMetrics(metrics, key+".", fv)
+		case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.Int:
+			for j := 0; j < fv.Len(); j++ {
+				metrics[key+"."+strconv.Itoa(j+1)] = float64(fv.Index(j).Int())
+			}
+		default:
+			if value, ok := metricValue(fv); ok {
+				metrics[key] = value
+			}
+		}
+	}
+}
+
+// metricValue converts a boolean or numeric value to float64.
+func metricValue(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, true
+		}
+		return 0, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// snakeCase converts a Go field name to snake_case, keeping acronyms
+// together: "ACPower" becomes "ac_power" and "VoltageMV" "voltage_mv".
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
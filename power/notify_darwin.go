@@ -0,0 +1,103 @@
+package power
+
+/*
+#cgo LDFLAGS: -framework CoreFoundation -framework IOKit
+
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/ps/IOPowerSources.h>
+
+// Implemented in Go (callbacks_darwin.go).
+extern void goPowerSourceChanged(uintptr_t handle);
+
+// Trampoline handed to IOKit; the context is the Go handle of the listener.
+static void power_source_callback(void *context) {
+    goPowerSourceChanged((uintptr_t)context);
+}
+
+// Creates a power source notification and attaches it to the calling
+// thread's run loop. Returns NULL on failure. The source is returned as an
+// opaque pointer so Go never has to handle the CF reference type directly.
+static void *add_power_source_notification(uintptr_t handle) {
+    CFRunLoopSourceRef source = IOPSNotificationCreateRunLoopSource(power_source_callback, (void *)handle);
+    if (source == NULL) return NULL;
+
+    CFRunLoopAddSource(CFRunLoopGetCurrent(), source, kCFRunLoopDefaultMode);
+    return (void *)source;
+}
+
+// Detaches and releases a source created by add_power_source_notification.
+// Must be called on the same thread that added it.
+static void remove_power_source_notification(void *opaque) {
+    CFRunLoopSourceRef source = (CFRunLoopSourceRef)opaque;
+    CFRunLoopRemoveSource(CFRunLoopGetCurrent(), source, kCFRunLoopDefaultMode);
+    CFRelease(source);
+}
+
+// Runs the current thread's run loop for at most the given number of seconds,
+// dispatching any pending notifications.
+static void run_loop_for(double seconds) {
+    CFRunLoopRunInMode(kCFRunLoopDefaultMode, seconds, false);
+}
+
+static double time_remaining_estimate(void) {
+    return IOPSGetTimeRemainingEstimate();
+}
+*/
+import "C"
+import (
+	"context"
+	"errors"
+	"runtime"
+	"runtime/cgo"
+	"time"
+)
+
+// runLoopTick bounds how long a watcher's run loop blocks before checking
+// whether its context has been cancelled.
+const runLoopTick = 0.25 // seconds
+
+// watchPowerSources delivers IOKit power source change notifications until
+// ctx is done. Each watcher gets its own run loop on a dedicated, locked OS
+// thread, so watchers never share run loop state. onChange is called on that
+// thread once up front to report the current state and then once per
+// notification; onStop is called on the same thread once the notification has
+// been torn down and onChange will not be called again.
+func watchPowerSources(ctx context.Context, onChange func(), onStop func()) error {
+	handle := cgo.NewHandle(onChange)
+	errc := make(chan error, 1)
+
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer handle.Delete()
+
+		source := C.add_power_source_notification(C.uintptr_t(handle))
+		if source == nil {
+			errc <- errors.New("power: failed to create power source notification")
+			return
+		}
+		errc <- nil
+
+		onChange()
+		for ctx.Err() == nil {
+			C.run_loop_for(runLoopTick)
+		}
+		C.remove_power_source_notification(source)
+		onStop()
+	}()
+
+	return <-errc
+}
+
+// timeRemainingEstimate converts IOPSGetTimeRemainingEstimate's seconds (or
+// negative sentinels) into a time.Duration.
+func timeRemainingEstimate() time.Duration {
+	seconds := float64(C.time_remaining_estimate())
+	switch {
+	case seconds == -2:
+		return TimeRemainingUnlimited
+	case seconds < 0:
+		return TimeRemainingUnknown
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,115 @@
+package power
+
+// Default thresholds used when the corresponding Options field is zero.
+const (
+	// DefaultWarrantyMinHealth is the capacity percentage Apple's service
+	// criteria require a battery to retain.
+	DefaultWarrantyMinHealth = 80
+
+	// DefaultWarrantyCycleLimit is the cycle count Apple rates current
+	// portables for.
+	DefaultWarrantyCycleLimit = 1000
+
+	// DefaultThermalThrottleCelsius is the battery temperature above which
+	// chargers commonly reduce the charge current.
+	DefaultThermalThrottleCelsius = 40.0
+)
+
+// DefaultHealthGradeThresholds are the minimum ConditionAdjustedHealth
+// percentages for the grades A, B, C and D. Anything lower is an F.
+var DefaultHealthGradeThresholds = [4]int{90, 80, 70, 60}
+
+// Options tunes how snapshots are read and interpreted. The zero value
+// reproduces the package defaults, so callers only need to set the fields they
+// care about.
+type Options struct {
+	// ClampHealth caps the health percentages in Calculations at 100. A
+	// freshly calibrated battery can report more capacity than its design
+	// rating (e.g., 103%); by default that raw value is kept.
+	ClampHealth bool
+
+	// AmperagePositiveWhenDischarging flips the sign of Battery.Amperage and
+	// Calculations.BatteryPower so that discharge reads positive. By default
+	// Apple's convention is kept: positive while charging, negative while
+	// discharging. SystemPower is unaffected, so the power-flow identity
+	// SystemPower = ACPower - BatteryPower becomes
+	// SystemPower = ACPower + BatteryPower when this is set.
+	//
+	// The option is named for the convention it switches to because Apple
+	// already reports charging as positive: an AmperagePositiveWhenCharging
+	// field would have to default to true, and the zero Options must keep
+	// the package defaults.
+	AmperagePositiveWhenDischarging bool
+
+	// SkipNested skips the nested BatteryData, AdapterDetails,
+	// PowerTelemetryData and ChargerData dictionaries during the read, for
+	// callers polling at high frequency that only need the top-level keys.
+	// The fields filled from them read as zero and their capabilities as
+	// absent: IndividualCellVoltages, GaugeStatus, MaxChargeCurrent,
+	// MaxDischargeCurrent, all of Adapter and Charger, and
+	// OptimizedChargingActive on systems that only report it inside
+	// ChargerData.
+	SkipNested bool
+
+	// Strict makes reads report expected keys that are absent, such as a
+	// missing BatteryData dictionary or DesignCapacity, instead of silently
+	// leaving their fields zero. All of them are returned together as a
+	// *MultiError of *MissingKeyError alongside the partial snapshot.
+	// Dictionaries skipped by SkipNested are not reported, and the
+	// AdapterDetails and PowerTelemetryData dictionaries are only expected
+	// while external power is connected.
+	Strict bool
+
+	// DecimalPlaces, when positive, rounds every voltage, current,
+	// temperature and power figure in the snapshot to that many decimal
+	// places, for consistent precision in output. Power is computed from the
+	// unrounded readings first. By default the readings keep full precision
+	// and the power figures are truncated to two decimal places.
+	DecimalPlaces int
+
+	// WarrantyMinHealth is the HealthByMaxCapacity percentage below which
+	// WarrantyFlag recommends service. Zero means DefaultWarrantyMinHealth.
+	WarrantyMinHealth int
+
+	// WarrantyCycleLimit is the cycle count up to which a battery is expected
+	// to retain WarrantyMinHealth. Zero means DefaultWarrantyCycleLimit.
+	WarrantyCycleLimit int
+
+	// HealthGradeThresholds are the minimum ConditionAdjustedHealth
+	// percentages for the grades A, B, C and D used by HealthGrade, in that
+	// order. The zero value means DefaultHealthGradeThresholds.
+	HealthGradeThresholds [4]int
+
+	// ThermalThrottleCelsius is the battery temperature above which
+	// IsThermallyThrottled attributes reduced charge current to heat. Zero
+	// means DefaultThermalThrottleCelsius.
+	ThermalThrottleCelsius float64
+}
+
+func (o Options) warrantyMinHealth() int {
+	if o.WarrantyMinHealth > 0 {
+		return o.WarrantyMinHealth
+	}
+	return DefaultWarrantyMinHealth
+}
+
+func (o Options) healthGradeThresholds() [4]int {
+	if o.HealthGradeThresholds != [4]int{} {
+		return o.HealthGradeThresholds
+	}
+	return DefaultHealthGradeThresholds
+}
+
+func (o Options) thermalThrottleCelsius() float64 {
+	if o.ThermalThrottleCelsius > 0 {
+		return o.ThermalThrottleCelsius
+	}
+	return DefaultThermalThrottleCelsius
+}
+
+func (o Options) warrantyCycleLimit() int {
+	if o.WarrantyCycleLimit > 0 {
+		return o.WarrantyCycleLimit
+	}
+	return DefaultWarrantyCycleLimit
+}
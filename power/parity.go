@@ -0,0 +1,24 @@
+package power
+
+import "errors"
+
+// ChargingParity reads the charging flag from both of macOS's battery APIs:
+// AppleSmartBattery's IsCharging (what State.IsCharging reports) and the
+// IOPowerSources description's kIOPSIsChargingKey (what the menu bar and
+// most other apps use). agree reports whether they match. The two are read
+// back to back and routinely disagree for a moment around plug and unplug,
+// so a brief mismatch is expected; a persistent one is worth investigating.
+func ChargingParity() (smartBattery, powerSources, agree bool, err error) {
+	state, err := ReadState()
+	if err != nil {
+		return false, false, false, err
+	}
+	powerSources, ok, err := powerSourcesIsCharging()
+	if err != nil {
+		return false, false, false, err
+	}
+	if !ok {
+		return false, false, false, errors.New("power: IOPowerSources lists no internal battery")
+	}
+	return state.IsCharging, powerSources, state.IsCharging == powerSources, nil
+}
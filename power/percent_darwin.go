@@ -0,0 +1,60 @@
+package power
+
+/*
+#cgo LDFLAGS: -framework CoreFoundation -framework IOKit
+
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/IOKitLib.h>
+
+// Reads a single integer property from a registry entry without copying its
+// whole property table. Returns 1 and sets *value on success, 0 otherwise.
+static int get_entry_long(io_registry_entry_t entry, const char *key, long *value) {
+    CFStringRef key_ref = CFStringCreateWithCString(NULL, key, kCFStringEncodingUTF8);
+    if (!key_ref) return 0;
+
+    CFTypeRef ref = IORegistryEntryCreateCFProperty(entry, key_ref, kCFAllocatorDefault, 0);
+    CFRelease(key_ref);
+    if (ref == NULL) return 0;
+
+    int ok = 0;
+    if (CFGetTypeID(ref) == CFNumberGetTypeID()) {
+        ok = CFNumberGetValue((CFNumberRef)ref, kCFNumberSInt64Type, value);
+    }
+    CFRelease(ref);
+    return ok;
+}
+
+// Reads just the current and full-charge capacity of the battery. Machines
+// without AppleRawMaxCapacity report MaxCapacity instead, possibly as a
+// percentage, so the design capacity is read too in that case for the caller
+// to convert it; otherwise *design is left 0. Returns 0 on success, 3 if
+// there is no battery service (as in get_battery_info) and 4 if a property
+// is missing.
+static int get_charge_capacities(long *current, long *max, long *design) {
+    // IOServiceGetMatchingService consumes the matching dictionary.
+    io_service_t battery = IOServiceGetMatchingService(kIOMainPortDefault, IOServiceMatching("AppleSmartBattery"));
+    if (battery == IO_OBJECT_NULL) return 3;
+
+    int ok = get_entry_long(battery, "AppleRawCurrentCapacity", current);
+    if (ok && !get_entry_long(battery, "AppleRawMaxCapacity", max)) {
+        ok = get_entry_long(battery, "MaxCapacity", max);
+        if (ok && !get_entry_long(battery, "DesignCapacity", design)) *design = 0;
+    }
+    IOObjectRelease(battery);
+    return ok ? 0 : 4;
+}
+*/
+import "C"
+
+// readChargeCapacities reads CurrentCapacity and MaxCapacity, in mAh, and
+// nothing else. A MaxCapacity reported as a percentage is converted to mAh
+// as a full read converts it; see normalizeMaxCapacity.
+func readChargeCapacities() (current, max int, err error) {
+	var cCurrent, cMax, cDesign C.long
+	if ret := C.get_charge_capacities(&cCurrent, &cMax, &cDesign); ret != 0 {
+		return 0, 0, &ioKitError{code: int(ret)}
+	}
+	b := Battery{MaxCapacity: int(cMax), DesignCapacity: int(cDesign)}
+	normalizeMaxCapacity(&b)
+	return int(cCurrent), b.MaxCapacity, nil
+}
@@ -0,0 +1,148 @@
+package power
+
+// ChargePhase is the stage of the charging cycle a lithium-ion pack is in.
+type ChargePhase int
+
+const (
+	// NotCharging means no current is flowing into the battery.
+	NotCharging ChargePhase = iota
+
+	// ConstantCurrent is the bulk phase, where the charger pushes close to
+	// its full current and the pack voltage rises. Charging is fastest here.
+	ConstantCurrent
+
+	// ConstantVoltage is the absorption phase, where the pack has reached
+	// its charge voltage and the current tapers off. This is why charging
+	// slows past roughly 80%.
+	ConstantVoltage
+
+	// Trickle is the final top-off, with only a small fraction of the full
+	// charge current flowing.
+	Trickle
+)
+
+// chargePhaseNames is indexed by ChargePhase.
+var chargePhaseNames = []string{
+	NotCharging:     "not-charging",
+	ConstantCurrent: "constant-current",
+	ConstantVoltage: "constant-voltage",
+	Trickle:         "trickle",
+}
+
+// String returns a lower-case name for the phase, such as
+// "constant-current".
+func (p ChargePhase) String() string {
+	if p < 0 || int(p) >= len(chargePhaseNames) {
+		return "unknown"
+	}
+	return chargePhaseNames[p]
+}
+
+const (
+	// constantCurrentRatio is the fraction of MaxChargeCurrent above which
+	// the charger is considered to be in its constant-current phase.
+	constantCurrentRatio = 0.7
+
+	// trickleRatio is the fraction of MaxChargeCurrent below which the
+	// charger is considered to be trickle charging.
+	trickleRatio = 0.1
+
+	// Charge percentages used to classify the phase when the gauge doesn't
+	// report MaxChargeCurrent.
+	constantVoltagePercent = 80
+	tricklePercent         = 95
+)
+
+// ChargePhase classifies the current charging stage from the charging
+// amperage relative to Battery.MaxChargeCurrent. When MaxChargeCurrent isn't
+// reported it falls back to the state of charge alone, which is a coarser
+// guess: constant current below 80%, constant voltage below 95%, trickle
+// above.
+func (b *BatteryInfo) ChargePhase() ChargePhase {
+	amperage := b.appleAmperage()
+	if !b.State.IsCharging || amperage <= 0 {
+		return NotCharging
+	}
+
+	if b.Has(CapMaxChargeCurrent) && b.Battery.MaxChargeCurrent > 0 {
+		ratio := amperage / b.Battery.MaxChargeCurrent
+		switch {
+		case ratio < trickleRatio:
+			return Trickle
+		case ratio >= constantCurrentRatio:
+			return ConstantCurrent
+		default:
+			return ConstantVoltage
+		}
+	}
+
+	switch percent := b.chargePercent(); {
+	case percent < constantVoltagePercent:
+		return ConstantCurrent
+	case percent < tricklePercent:
+		return ConstantVoltage
+	default:
+		return Trickle
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler using the String names.
+func (p ChargePhase) MarshalText() ([]byte, error) {
+	return enumText(p, chargePhaseNames)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *ChargePhase) UnmarshalText(text []byte) error {
+	v, err := parseEnumText[ChargePhase](text, chargePhaseNames)
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// throttledCurrentRatio is the fraction of MaxChargeCurrent below which a
+// hot battery's charging is considered throttled.
+const throttledCurrentRatio = 0.5
+
+// IsThermallyThrottled reports whether charging appears to be held back by
+// heat, using the default temperature threshold. See
+// IsThermallyThrottledWithOptions.
+func (b *BatteryInfo) IsThermallyThrottled() bool {
+	return b.IsThermallyThrottledWithOptions(Options{})
+}
+
+// IsThermallyThrottledWithOptions reports whether charging appears to be held
+// back by heat: the battery is charging, is above opts.ThermalThrottleCelsius,
+// and draws less than half of Battery.MaxChargeCurrent while still below the
+// charge level where the current tapers on its own (see ChargePhase). It is
+// false when MaxChargeCurrent isn't reported, since a low current can't be
+// judged without it.
+func (b *BatteryInfo) IsThermallyThrottledWithOptions(opts Options) bool {
+	if !b.Has(CapMaxChargeCurrent) || b.Battery.MaxChargeCurrent <= 0 {
+		return false
+	}
+	amperage := b.appleAmperage()
+	return b.State.IsCharging && amperage > 0 &&
+		b.Battery.Temperature > opts.thermalThrottleCelsius() &&
+		amperage < b.Battery.MaxChargeCurrent*throttledCurrentRatio &&
+		b.chargePercent() < constantVoltagePercent
+}
+
+// powerLimitedRatio is the fraction of Battery.MaxDischargeCurrent above which
+// the battery is considered close to the most it has ever delivered.
+const powerLimitedRatio = 0.9
+
+// IsPowerLimited reports whether the battery looks like the bottleneck for
+// system performance: the machine is running on battery and drawing at least
+// 90% of Battery.MaxDischargeCurrent, the highest current the gauge has
+// recorded the pack delivering. IOKit exposes no direct power-limit flag, so
+// this is an inference from how close the load is to that rating. It is
+// false when MaxDischargeCurrent isn't reported.
+func (b *BatteryInfo) IsPowerLimited() bool {
+	if !b.Has(CapMaxDischargeCurrent) || b.Battery.MaxDischargeCurrent <= 0 {
+		return false
+	}
+	draw := -b.appleAmperage()
+	return !b.State.IsConnected && draw >= b.Battery.MaxDischargeCurrent*powerLimitedRatio
+}
@@ -0,0 +1,239 @@
+package power
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parsePlistXML decodes an XML property list, as produced by
+// CFPropertyListCreateData, into plain Go values. See GetPropertiesAtPath for
+// the type mapping.
+func parsePlistXML(data []byte) (any, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local == "plist" {
+			continue
+		}
+		return decodePlistValue(d, start)
+	}
+}
+
+// decodePlistValue decodes the element opened by start, consuming its end
+// element.
+func decodePlistValue(d *xml.Decoder, start xml.StartElement) (any, error) {
+	switch start.Name.Local {
+	case "dict":
+		dict := map[string]any{}
+		for {
+			key, ok, err := nextPlistElement(d)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return dict, nil
+			}
+			if key.Name.Local != "key" {
+				return nil, fmt.Errorf("plist: expected <key>, got <%s>", key.Name.Local)
+			}
+			var name string
+			if err := d.DecodeElement(&name, &key); err != nil {
+				return nil, err
+			}
+			value, ok, err := nextPlistElement(d)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, fmt.Errorf("plist: key %q has no value", name)
+			}
+			if dict[name], err = decodePlistValue(d, value); err != nil {
+				return nil, err
+			}
+		}
+	case "array":
+		array := []any{}
+		for {
+			elem, ok, err := nextPlistElement(d)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return array, nil
+			}
+			value, err := decodePlistValue(d, elem)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+	case "true", "false":
+		if err := d.Skip(); err != nil {
+			return nil, err
+		}
+		return start.Name.Local == "true", nil
+	}
+
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return nil, err
+	}
+	switch start.Name.Local {
+	case "string":
+		return text, nil
+	case "integer":
+		text = strings.TrimSpace(text)
+		if i, err := strconv.ParseInt(text, 0, 64); err == nil {
+			return i, nil
+		}
+		return strconv.ParseUint(text, 0, 64)
+	case "real":
+		return strconv.ParseFloat(strings.TrimSpace(text), 64)
+	case "data":
+		return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+	case "date":
+		return time.Parse(time.RFC3339, strings.TrimSpace(text))
+	}
+	return nil, fmt.Errorf("plist: unknown element <%s>", start.Name.Local)
+}
+
+// nextPlistElement returns the next child element, or ok == false once the
+// enclosing element ends.
+func nextPlistElement(d *xml.Decoder) (start xml.StartElement, ok bool, err error) {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return xml.StartElement{}, false, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			return t, true, nil
+		case xml.EndElement:
+			return xml.StartElement{}, false, nil
+		}
+	}
+}
+
+// plistHeader opens an XML property list as written by Apple's tools.
+const plistHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+`
+
+// MarshalPlist encodes the snapshot as an XML property list, for tools that
+// consume plists such as the output of `ioreg -a`. As there, each struct
+// becomes a <dict> keyed by its CamelCase field names, in declaration order;
+// map keys are sorted. ReadAt is a <date>, durations are <real> seconds,
+// and enum-like types use their text form. A nil snapshot is an error.
+func (b *BatteryInfo) MarshalPlist() ([]byte, error) {
+	if b == nil {
+		return nil, errors.New("power: MarshalPlist of nil *BatteryInfo")
+	}
+	var buf bytes.Buffer
+	buf.WriteString(plistHeader)
+	if err := encodePlistValue(&buf, reflect.ValueOf(b).Elem(), 0); err != nil {
+		return nil, err
+	}
+	buf.WriteString("</plist>\n")
+	return buf.Bytes(), nil
+}
+
+// encodePlistValue writes v as a plist element indented by depth tabs.
+func encodePlistValue(buf *bytes.Buffer, v reflect.Value, depth int) error {
+	indent := strings.Repeat("\t", depth)
+	element := func(tag, text string) {
+		buf.WriteString(indent + "<" + tag + ">")
+		xml.EscapeText(buf, []byte(text))
+		buf.WriteString("</" + tag + ">\n")
+	}
+
+	switch {
+	case v.Type() == timeType:
+		element("date", v.Interface().(time.Time).UTC().Format(time.RFC3339))
+		return nil
+	case v.Type() == durationType:
+		element("real", strconv.FormatFloat(v.Interface().(time.Duration).Seconds(), 'g', -1, 64))
+		return nil
+	case v.Type().Implements(textMarshalerType):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		element("string", string(text))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		buf.WriteString(indent + "<" + strconv.FormatBool(v.Bool()) + "/>\n")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		element("integer", strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		element("integer", strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		element("real", strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.String:
+		element("string", v.String())
+	case reflect.Pointer:
+		if v.IsNil() {
+			buf.WriteString(indent + "<dict/>\n")
+			return nil
+		}
+		return encodePlistValue(buf, v.Elem(), depth)
+	case reflect.Slice, reflect.Array:
+		buf.WriteString(indent + "<array>\n")
+		for i := 0; i < v.Len(); i++ {
+			if err := encodePlistValue(buf, v.Index(i), depth+1); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(indent + "</array>\n")
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		values := map[string]reflect.Value{}
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+			values[k.String()] = v.MapIndex(k)
+		}
+		sort.Strings(keys)
+		buf.WriteString(indent + "<dict>\n")
+		for _, k := range keys {
+			buf.WriteString(indent + "\t<key>")
+			xml.EscapeText(buf, []byte(k))
+			buf.WriteString("</key>\n")
+			if err := encodePlistValue(buf, values[k], depth+1); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(indent + "</dict>\n")
+	case reflect.Struct:
+		t := v.Type()
+		buf.WriteString(indent + "<dict>\n")
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			buf.WriteString(indent + "\t<key>" + t.Field(i).Name + "</key>\n")
+			if err := encodePlistValue(buf, v.Field(i), depth+1); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(indent + "</dict>\n")
+	default:
+		return fmt.Errorf("plist: unsupported type %s", v.Type())
+	}
+	return nil
+}
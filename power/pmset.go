@@ -0,0 +1,49 @@
+package power
+
+import "fmt"
+
+// gaugeTimeUnknown is the AvgTimeToEmpty/AvgTimeToFull value the gauge
+// reports while it has no estimate.
+const gaugeTimeUnknown = 65535
+
+// PmsetFormat renders the snapshot in the layout of `pmset -g batt`, so that
+// scripts parsing that command can be fed from a native read instead:
+//
+//	Now drawing from 'AC Power'
+//	 -InternalBattery-0 (id=0)	82%; charging; 1:05 remaining present: true
+//
+// The charging state is "charged", "charging", "AC attached; not charging"
+// or "discharging". Like pmset, the not-charging state has no time segment,
+// and the time is "(no estimate)" while the gauge is still calculating.
+// pmset's id is an IOPowerSources identifier this package doesn't read, so
+// it is always 0.
+func (b *BatteryInfo) PmsetFormat() string {
+	source := "Battery Power"
+	if b.State.IsConnected {
+		source = "AC Power"
+	}
+
+	var state string
+	minutes, timed := gaugeTimeUnknown, true
+	switch {
+	case b.State.IsConnected && b.State.FullyCharged:
+		state, minutes = "charged", 0
+	case b.State.IsCharging:
+		state, minutes = "charging", b.Battery.TimeToFull
+	case b.State.IsConnected:
+		state, timed = "AC attached; not charging", false
+	default:
+		state, minutes = "discharging", b.Battery.TimeToEmpty
+	}
+
+	switch {
+	case !timed:
+	case minutes >= 0 && minutes < gaugeTimeUnknown:
+		state += fmt.Sprintf("; %d:%02d remaining", minutes/60, minutes%60)
+	default:
+		state += "; (no estimate)"
+	}
+
+	return fmt.Sprintf("Now drawing from '%s'\n -InternalBattery-0 (id=0)\t%d%%; %s present: %t\n",
+		source, b.chargePercent(), state, b.State.BatteryInstalled || b.Battery.DesignCapacity > 0)
+}
@@ -0,0 +1,56 @@
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// pmsetID matches the IOPowerSources id in pmset's output, which
+// PmsetFormat always renders as 0.
+var pmsetID = regexp.MustCompile(`\(id=\d+\)`)
+
+// TestPmsetFormat compares PmsetFormat against `pmset -g batt` output for
+// each charging state, kept in testdata/pmset.
+func TestPmsetFormat(t *testing.T) {
+	snapshot := func(connected, charging, full bool, current, toEmpty, toFull int) *BatteryInfo {
+		return &BatteryInfo{
+			State: State{
+				IsConnected:      connected,
+				IsCharging:       charging,
+				FullyCharged:     full,
+				BatteryInstalled: true,
+			},
+			Battery: Battery{
+				MaxCapacity:     100,
+				CurrentCapacity: current,
+				TimeToEmpty:     toEmpty,
+				TimeToFull:      toFull,
+			},
+		}
+	}
+	tests := []struct {
+		fixture string
+		info    *BatteryInfo
+	}{
+		{"discharging.txt", snapshot(false, false, false, 78, 312, gaugeTimeUnknown)},
+		{"discharging-no-estimate.txt", snapshot(false, false, false, 87, gaugeTimeUnknown, gaugeTimeUnknown)},
+		{"charging.txt", snapshot(true, true, false, 45, gaugeTimeUnknown, 83)},
+		{"charging-no-estimate.txt", snapshot(true, true, false, 60, gaugeTimeUnknown, gaugeTimeUnknown)},
+		{"charged.txt", snapshot(true, false, true, 100, gaugeTimeUnknown, 0)},
+		{"ac-attached-not-charging.txt", snapshot(true, false, false, 80, gaugeTimeUnknown, gaugeTimeUnknown)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "pmset", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := pmsetID.ReplaceAllString(string(data), "(id=0)")
+			if got := tt.info.PmsetFormat(); got != want {
+				t.Errorf("PmsetFormat() =\n%q\nwant\n%q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package power
+
+/*
+#cgo LDFLAGS: -framework CoreFoundation -framework IOKit
+
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/ps/IOPowerSources.h>
+#include <IOKit/ps/IOPSKeys.h>
+
+#include "cfhelpers.h"
+
+// Looks up kIOPSIsChargingKey for the internal battery through the
+// IOPowerSources API. Returns 1 and sets *charging if an internal battery was
+// found, 0 if there is none, and -1 if the power sources can't be read.
+static int iops_internal_battery_charging(int *charging) {
+    CFTypeRef blob = IOPSCopyPowerSourcesInfo();
+    if (blob == NULL) return -1;
+    CFArrayRef list = IOPSCopyPowerSourcesList(blob);
+    if (list == NULL) {
+        CFRelease(blob);
+        return -1;
+    }
+
+    int found = 0;
+    char type[64];
+    for (CFIndex i = 0; i < CFArrayGetCount(list) && !found; i++) {
+        // IOPSGetPowerSourceDescription follows the Get rule.
+        CFDictionaryRef desc = IOPSGetPowerSourceDescription(blob, CFArrayGetValueAtIndex(list, i));
+        if (desc == NULL) continue;
+
+        get_string_prop(desc, kIOPSTypeKey, type, sizeof(type));
+        if (strcmp(type, kIOPSInternalBatteryType) != 0) continue;
+
+        *charging = get_bool_prop(desc, kIOPSIsChargingKey);
+        found = 1;
+    }
+
+    CFRelease(list);
+    CFRelease(blob);
+    return found;
+}
+*/
+import "C"
+import "errors"
+
+// powerSourcesIsCharging reports the internal battery's kIOPSIsChargingKey.
+// ok is false when IOPowerSources lists no internal battery.
+func powerSourcesIsCharging() (charging, ok bool, err error) {
+	var cCharging C.int
+	switch C.iops_internal_battery_charging(&cCharging) {
+	case -1:
+		return false, false, errors.New("power: failed to read IOPowerSources info")
+	case 0:
+		return false, false, nil
+	}
+	return cCharging != 0, true, nil
+}
@@ -0,0 +1,72 @@
+package power
+
+import "strings"
+
+// designCycleCounts maps battery identifiers to Apple's rated cycle count.
+// Keys are lower-case and may be either a gas-gauge chip name (as reported in
+// Battery.DeviceName) or a hardware model identifier (as reported by
+// `sysctl hw.model`) for older machines whose rating differs from their
+// gauge's era. Add new hardware here.
+var designCycleCounts = map[string]int{
+	// Gas-gauge chips used in 1000-cycle portables (2009 and later).
+	"bq20z451": 1000,
+	"bq20z45":  1000,
+	"bq40z651": 1000,
+	"bq40z655": 1000,
+
+	// 300-cycle models (2006 to early 2008).
+	"macbook1,1":    300,
+	"macbook2,1":    300,
+	"macbook3,1":    300,
+	"macbook4,1":    300,
+	"macbookair1,1": 300,
+	"macbookair2,1": 300,
+	"macbookpro1,1": 300,
+	"macbookpro1,2": 300,
+	"macbookpro2,1": 300,
+	"macbookpro2,2": 300,
+	"macbookpro3,1": 300,
+	"macbookpro4,1": 300,
+
+	// 500-cycle models (late 2008).
+	"macbook5,1":    500,
+	"macbookpro5,1": 500,
+}
+
+// DesignCycleCount returns the cycle count Apple rates a battery for, looked
+// up by gas-gauge chip name or hardware model identifier. ok is false for
+// unknown hardware, so callers can fall back to their own default (such as
+// DefaultWarrantyCycleLimit).
+func DesignCycleCount(deviceName string) (cycles int, ok bool) {
+	cycles, ok = designCycleCounts[strings.ToLower(strings.TrimSpace(deviceName))]
+	return cycles, ok
+}
+
+// advertisedWh maps lower-case hardware model identifiers (as reported by
+// `sysctl hw.model`) to the battery energy Apple advertises for that model,
+// in Wh. Add new hardware here.
+var advertisedWh = map[string]float64{
+	"macbookair10,1": 49.9,  // MacBook Air (M1, 2020)
+	"mac14,2":        52.6,  // MacBook Air (13-inch, M2, 2022)
+	"mac14,15":       66.5,  // MacBook Air (15-inch, M2, 2023)
+	"mac15,12":       52.6,  // MacBook Air (13-inch, M3, 2024)
+	"mac15,13":       66.5,  // MacBook Air (15-inch, M3, 2024)
+	"macbookpro17,1": 58.2,  // MacBook Pro (13-inch, M1, 2020)
+	"mac14,7":        58.2,  // MacBook Pro (13-inch, M2, 2022)
+	"macbookpro18,3": 70.0,  // MacBook Pro (14-inch, 2021), M1 Pro
+	"macbookpro18,4": 70.0,  // MacBook Pro (14-inch, 2021), M1 Max
+	"macbookpro18,1": 100.0, // MacBook Pro (16-inch, 2021), M1 Pro
+	"macbookpro18,2": 100.0, // MacBook Pro (16-inch, 2021), M1 Max
+	"mac14,5":        70.0,  // MacBook Pro (14-inch, 2023), M2 Max
+	"mac14,9":        70.0,  // MacBook Pro (14-inch, 2023), M2 Pro
+	"mac14,6":        100.0, // MacBook Pro (16-inch, 2023), M2 Max
+	"mac14,10":       100.0, // MacBook Pro (16-inch, 2023), M2 Pro
+}
+
+// AdvertisedWh returns the battery energy Apple advertises for a hardware
+// model identifier such as "Mac14,2" (see HardwareModel), for comparing
+// against EnergyWh or DesignEnergyWh. ok is false for unknown models.
+func AdvertisedWh(model string) (wh float64, ok bool) {
+	wh, ok = advertisedWh[strings.ToLower(strings.TrimSpace(model))]
+	return wh, ok
+}
@@ -0,0 +1,78 @@
+package power
+
+// readSections selects which parts of a snapshot a read populates. The
+// values must match the SECTION_* defines in the C code.
+type readSections int
+
+const (
+	sectionState readSections = 1 << iota
+	sectionBattery
+	sectionAdapter
+	sectionCharger
+	sectionNested   // follow the nested dictionaries of the other sections
+	sectionIdentity // the Battery identity strings
+
+	sectionAll = sectionState | sectionBattery | sectionAdapter | sectionCharger | sectionNested | sectionIdentity
+)
+
+// GetBatteryInfo queries IOKit for all available power and battery telemetry
+// and returns it in a structured format.
+//
+// GetBatteryInfo is safe for concurrent use: every call uses its own C struct
+// and acquires and releases its own IOKit references, and no package state is
+// shared between calls. Callers that want IOKit reads to never overlap can use
+// a SafeReader instead.
+func GetBatteryInfo() (*BatteryInfo, error) {
+	return GetBatteryInfoWithOptions(Options{})
+}
+
+// GetBatteryInfoWithOptions is like GetBatteryInfo, but applies opts while
+// reading and deriving the snapshot. It has the same concurrency guarantees.
+//
+// With opts.Strict, it can return both a snapshot and a *MultiError listing
+// the expected keys that were missing; the snapshot is complete apart from
+// the fields those keys would have filled.
+func GetBatteryInfoWithOptions(opts Options) (*BatteryInfo, error) {
+	sections := sectionAll
+	if opts.SkipNested {
+		sections &^= sectionNested
+	}
+	info, err := readBatteryInfo(opts, sections, nil)
+	if info == nil {
+		return nil, err
+	}
+
+	// Calculate derived health metrics based on the collected data.
+	calculateDerivedMetrics(info, opts)
+	return info, err
+}
+
+// ReadAll performs a single IOKit read and returns the snapshot decomposed
+// into its parts, all from the same instant.
+func ReadAll() (State, Battery, Adapter, Calculations, error) {
+	info, err := GetBatteryInfo()
+	if err != nil {
+		return State{}, Battery{}, Adapter{}, Calculations{}, err
+	}
+	return info.State, info.Battery, info.Adapter, info.Calculations, nil
+}
+
+// ReadState reads only the charging State, skipping the battery and adapter
+// keys and their nested dictionaries.
+func ReadState() (State, error) {
+	info, err := readBatteryInfo(Options{}, sectionState|sectionNested, nil)
+	if err != nil {
+		return State{}, err
+	}
+	return info.State, nil
+}
+
+// ReadAdapter reads only the Adapter details and live input telemetry,
+// skipping the state and battery keys.
+func ReadAdapter() (Adapter, error) {
+	info, err := readBatteryInfo(Options{}, sectionAdapter|sectionNested, nil)
+	if err != nil {
+		return Adapter{}, err
+	}
+	return info.Adapter, nil
+}
@@ -0,0 +1,23 @@
+package power
+
+import "sync"
+
+// SafeReader serializes battery reads so that at most one IOKit query is in
+// flight at a time through it. Use a single SafeReader shared between
+// goroutines when heavy concurrent polling should not fan out into parallel
+// CGO calls.
+//
+// The zero value is ready to use. A SafeReader must not be copied after first
+// use.
+type SafeReader struct {
+	mu sync.Mutex
+}
+
+// GetBatteryInfo behaves like the package-level GetBatteryInfo, but blocks
+// until any other read through the same SafeReader has finished. It is safe
+// for concurrent use.
+func (r *SafeReader) GetBatteryInfo() (*BatteryInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return GetBatteryInfo()
+}
@@ -0,0 +1,52 @@
+package power
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RegistryPlane names an I/O Registry plane, which determines how entries are
+// related to one another and therefore how registry paths are resolved.
+type RegistryPlane string
+
+const (
+	// PlaneService is the IOService plane, where drivers and devices such as
+	// AppleSmartBattery are attached. It is the default.
+	PlaneService RegistryPlane = "IOService"
+
+	// PlanePower is the IOPower plane, which arranges entries by their
+	// power-management dependencies.
+	PlanePower RegistryPlane = "IOPower"
+)
+
+// GetPropertiesAtPath returns every property of the I/O Registry entry at
+// path, resolved in the given plane, as decoded property-list values:
+// map[string]any for dictionaries, []any for arrays, and string, int64 (or
+// uint64 when it overflows), float64, bool, []byte and time.Time for scalars.
+//
+// The path may be plane-qualified (e.g., "IOPower:/IOPowerConnection"), in
+// which case plane is ignored; otherwise it is resolved in plane. The zero
+// plane means PlaneService. It is a read-only query and safe for concurrent
+// use.
+func GetPropertiesAtPath(path string, plane RegistryPlane) (map[string]any, error) {
+	if plane == "" {
+		plane = PlaneService
+	}
+	if !strings.Contains(path, ":") {
+		path = string(plane) + ":" + path
+	}
+
+	data, err := copyRegistryPropertiesXML(path)
+	if err != nil {
+		return nil, err
+	}
+	value, err := parsePlistXML(data)
+	if err != nil {
+		return nil, fmt.Errorf("power: decoding properties of %s: %w", path, err)
+	}
+	props, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("power: properties of %s are not a dictionary", path)
+	}
+	return props, nil
+}
@@ -0,0 +1,105 @@
+package power
+
+/*
+#cgo LDFLAGS: -framework CoreFoundation -framework IOKit
+
+#include <stdlib.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/IOKitLib.h>
+
+// Serializes every property of entry as an XML property list and releases
+// entry. Returns a CFDataRef the caller must release, or NULL with *error set
+// to 1 if the entry doesn't exist and 2 if its properties can't be read.
+// CF types are returned as void* so the Go side can compare against nil.
+static void *copy_entry_properties_xml(io_registry_entry_t entry, int *error) {
+    if (entry == IO_OBJECT_NULL) {
+        *error = 1;
+        return NULL;
+    }
+
+    CFMutableDictionaryRef properties = NULL;
+    kern_return_t result = IORegistryEntryCreateCFProperties(entry, &properties, kCFAllocatorDefault, 0);
+    IOObjectRelease(entry);
+    if (result != KERN_SUCCESS || properties == NULL) {
+        *error = 2;
+        return NULL;
+    }
+
+    CFDataRef data = CFPropertyListCreateData(kCFAllocatorDefault, properties, kCFPropertyListXMLFormat_v1_0, 0, NULL);
+    CFRelease(properties);
+    if (data == NULL) {
+        *error = 2;
+        return NULL;
+    }
+    return (void *)data;
+}
+
+// copy_entry_properties_xml for the registry entry at path.
+static void *copy_registry_properties_xml(const char *path, int *error) {
+    return copy_entry_properties_xml(IORegistryEntryFromPath(kIOMainPortDefault, path), error);
+}
+
+// copy_entry_properties_xml for the first service of the given class.
+static void *copy_service_properties_xml(const char *class_name, int *error) {
+    // IOServiceGetMatchingService consumes the matching dictionary.
+    CFMutableDictionaryRef matching = IOServiceMatching(class_name);
+    if (matching == NULL) {
+        *error = 1;
+        return NULL;
+    }
+    return copy_entry_properties_xml(IOServiceGetMatchingService(kIOMainPortDefault, matching), error);
+}
+
+static const void *data_bytes(void *data) {
+    return CFDataGetBytePtr((CFDataRef)data);
+}
+
+static long data_length(void *data) {
+    return (long)CFDataGetLength((CFDataRef)data);
+}
+
+static void release_data(void *data) {
+    CFRelease((CFTypeRef)data);
+}
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// copyRegistryPropertiesXML returns the properties of the registry entry at
+// the plane-qualified path as an XML property list.
+func copyRegistryPropertiesXML(path string) ([]byte, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	var cErr C.int
+	data := C.copy_registry_properties_xml(cPath, &cErr)
+	if data == nil {
+		if cErr == 1 {
+			return nil, fmt.Errorf("power: no registry entry at %s", path)
+		}
+		return nil, fmt.Errorf("power: reading properties of %s failed", path)
+	}
+	defer C.release_data(data)
+	return C.GoBytes(C.data_bytes(data), C.int(C.data_length(data))), nil
+}
+
+// copyServicePropertiesXML returns the properties of the first service of the
+// given I/O Registry class as an XML property list.
+func copyServicePropertiesXML(class string) ([]byte, error) {
+	cClass := C.CString(class)
+	defer C.free(unsafe.Pointer(cClass))
+
+	var cErr C.int
+	data := C.copy_service_properties_xml(cClass, &cErr)
+	if data == nil {
+		if cErr == 1 {
+			return nil, &ioKitError{code: ioErrNoService}
+		}
+		return nil, fmt.Errorf("power: reading properties of %s failed", class)
+	}
+	defer C.release_data(data)
+	return C.GoBytes(C.data_bytes(data), C.int(C.data_length(data))), nil
+}
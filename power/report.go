@@ -0,0 +1,38 @@
+package power
+
+// PowerReport is the complete power picture of the machine from a single
+// call: the battery and adapter telemetry plus, where available, the total
+// system power measured by the SMC.
+type PowerReport struct {
+	// Info is the battery, adapter and charger snapshot, as returned by
+	// GetBatteryInfo.
+	Info *BatteryInfo
+
+	// SMCSystemPower is the total system power in Watts as measured by the
+	// SMC, which includes draw the battery gauge can't see. It is only
+	// meaningful when SMCAvailable is true.
+	SMCSystemPower float64
+
+	// SMCAvailable reports whether the SMC reading was obtained. It is
+	// false when the SMC can't be opened or doesn't have the PSTR key, as on
+	// some older models; Info.Calculations.SystemPower is then the best
+	// available system figure.
+	SMCAvailable bool
+}
+
+// GetPowerReport reads the battery telemetry and any SMC power readings and
+// combines them into a PowerReport. Missing SMC keys are not an error; the
+// report simply has SMCAvailable set to false. Only a failed battery read
+// fails the call.
+func GetPowerReport() (*PowerReport, error) {
+	info, err := GetBatteryInfo()
+	if err != nil {
+		return nil, err
+	}
+	report := &PowerReport{Info: info}
+	if watts, err := readSMCSystemPower(); err == nil {
+		report.SMCSystemPower = watts
+		report.SMCAvailable = true
+	}
+	return report, nil
+}
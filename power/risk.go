@@ -0,0 +1,126 @@
+package power
+
+import "fmt"
+
+// RiskLevel grades how likely a battery is to be failing or swelling.
+type RiskLevel int
+
+const (
+	// RiskLow means no warning signs were found.
+	RiskLow RiskLevel = iota
+
+	// RiskElevated means at least one early warning sign was found; keep an
+	// eye on the battery and have it checked if the sign persists.
+	RiskElevated
+
+	// RiskHigh means a strong sign of failure was found; the battery should
+	// be inspected, and not left charging unattended, soon.
+	RiskHigh
+)
+
+// riskLevelNames is indexed by RiskLevel.
+var riskLevelNames = []string{
+	RiskLow:      "low",
+	RiskElevated: "elevated",
+	RiskHigh:     "high",
+}
+
+// String returns a lower-case name for the level, such as "elevated".
+func (r RiskLevel) String() string {
+	if r < 0 || int(r) >= len(riskLevelNames) {
+		return "unknown"
+	}
+	return riskLevelNames[r]
+}
+
+// MarshalText implements encoding.TextMarshaler using the String names.
+func (r RiskLevel) MarshalText() ([]byte, error) {
+	return enumText(r, riskLevelNames)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *RiskLevel) UnmarshalText(text []byte) error {
+	v, err := parseEnumText[RiskLevel](text, riskLevelNames)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+// FailureRisk thresholds. The drift figures continue the scale used for
+// ConditionAdjustedHealth, whose worst band starts at 50 mV.
+const (
+	riskDriftElevatedMV = 50
+	riskDriftHighMV     = 100
+
+	riskCellLowMV  = 3000 // below the discharge cut-off of a lithium-ion cell
+	riskCellHighMV = 4450 // above the charge voltage of any Mac pack
+
+	riskTempElevatedCelsius = 45.0
+	riskTempHighCelsius     = 55.0
+
+	riskHealthElevated = 60
+	riskHealthHigh     = 40
+)
+
+// FailureRisk combines the warning signs of a failing or swelling battery
+// into a single risk level, and returns a human-readable reason for each sign
+// found. The level is that of the most serious sign; reasons is nil when the
+// risk is low. The signs are:
+//
+//   - a permanent failure latched by the gauge (high);
+//   - cell voltages drifting apart by more than 50 mV (elevated) or
+//     100 mV (high), or any cell outside the safe lithium-ion range (high);
+//   - a battery temperature above 45°C (elevated) or 55°C (high);
+//   - the full-charge capacity collapsed below 60% (elevated) or 40% (high)
+//     of its design capacity.
+//
+// A single snapshot can't tell a brief spike under heavy load from a
+// persistent fault, so act on signs that recur across readings. This is a
+// heuristic, not a diagnosis; a visibly swollen battery needs attention
+// whatever it reports.
+func (b *BatteryInfo) FailureRisk() (level RiskLevel, reasons []string) {
+	flag := func(l RiskLevel, format string, args ...any) {
+		level = max(level, l)
+		reasons = append(reasons, fmt.Sprintf(format, args...))
+	}
+
+	if b.Battery.PermanentFailureStatus != 0 {
+		flag(RiskHigh, "gauge reports permanent failure status 0x%x", b.Battery.PermanentFailureStatus)
+	}
+
+	if cells := b.Battery.IndividualCellVoltages; len(cells) > 0 {
+		minV, maxV := findMinMax(cells)
+		switch drift := maxV - minV; {
+		case len(cells) < 2:
+		case drift > riskDriftHighMV:
+			flag(RiskHigh, "cell voltages differ by %d mV", drift)
+		case drift > riskDriftElevatedMV:
+			flag(RiskElevated, "cell voltages differ by %d mV", drift)
+		}
+		if minV < riskCellLowMV {
+			flag(RiskHigh, "a cell is at %d mV, below the safe minimum", minV)
+		}
+		if maxV > riskCellHighMV {
+			flag(RiskHigh, "a cell is at %d mV, above the safe maximum", maxV)
+		}
+	}
+
+	switch temp := b.Battery.Temperature; {
+	case temp > riskTempHighCelsius:
+		flag(RiskHigh, "battery temperature is %.1f°C", temp)
+	case temp > riskTempElevatedCelsius:
+		flag(RiskElevated, "battery temperature is %.1f°C", temp)
+	}
+
+	if b.Battery.DesignCapacity > 0 && b.Battery.MaxCapacity > 0 {
+		switch health := b.Calculations.HealthByMaxCapacity; {
+		case health < riskHealthHigh:
+			flag(RiskHigh, "full-charge capacity has fallen to %d%% of design", health)
+		case health < riskHealthElevated:
+			flag(RiskElevated, "full-charge capacity has fallen to %d%% of design", health)
+		}
+	}
+	return level, reasons
+}
@@ -0,0 +1,74 @@
+package power
+
+import (
+	"math"
+	"time"
+)
+
+// Sample flag bits.
+const (
+	SampleCharging     uint8 = 1 << iota // State.IsCharging
+	SampleConnected                      // State.IsConnected
+	SampleFullyCharged                   // State.FullyCharged
+)
+
+// Sample is a flat, fixed-width summary of a snapshot for compact binary
+// logs. Every field has a fixed size, so a Sample can be written with
+// encoding/binary (binary.Size reports 32 bytes), and a log of them can be
+// read back by anyone using this definition. Fields are only ever appended,
+// never reordered.
+//
+// Currents and powers are always in Apple's sign convention (positive when
+// charging), whatever Options the snapshot was read with. Values that don't
+// fit a field are clamped to its range.
+type Sample struct {
+	UnixMilli    int64  // sample time in milliseconds since the Unix epoch
+	ChargePct    uint8  // state of charge, 0–100
+	Flags        uint8  // Sample* bits
+	TempCentideg int16  // battery temperature in hundredths of a degree Celsius
+	VoltageMV    uint16 // pack voltage in mV
+	AmperageMA   int16  // pack current in mA
+	BatteryMW    int32  // power into (+) or out of (-) the battery in mW
+	SystemMW     int32  // power consumed by the rest of the system in mW
+	ACMW         int32  // power drawn from the adapter in mW
+	CycleCount   uint16 // charge cycle count
+	HealthPct    uint16 // Calculations.HealthByMaxCapacity
+}
+
+// ToSample returns the Sample for the snapshot, stamped with t.
+func (b *BatteryInfo) ToSample(t time.Time) Sample {
+	var flags uint8
+	if b.State.IsCharging {
+		flags |= SampleCharging
+	}
+	if b.State.IsConnected {
+		flags |= SampleConnected
+	}
+	if b.State.FullyCharged {
+		flags |= SampleFullyCharged
+	}
+	return Sample{
+		UnixMilli:    t.UnixMilli(),
+		ChargePct:    uint8(clampInt(b.chargePercent(), 0, math.MaxUint8)),
+		Flags:        flags,
+		TempCentideg: int16(clampInt(b.Battery.TemperatureCentidegrees, math.MinInt16, math.MaxInt16)),
+		VoltageMV:    uint16(clampInt(b.Battery.VoltageMV, 0, math.MaxUint16)),
+		AmperageMA:   int16(clampInt(b.Battery.AmperageMA, math.MinInt16, math.MaxInt16)),
+		BatteryMW:    milliwatts(b.Calculations.appleBatteryPower()),
+		SystemMW:     milliwatts(b.Calculations.SystemPower),
+		ACMW:         milliwatts(b.Calculations.ACPower),
+		CycleCount:   uint16(clampInt(b.Battery.CycleCount, 0, math.MaxUint16)),
+		HealthPct:    uint16(clampInt(b.Calculations.HealthByMaxCapacity, 0, math.MaxUint16)),
+	}
+}
+
+// milliwatts converts Watts to whole mW, clamped to the range of an int32.
+func milliwatts(watts float64) int32 {
+	mw := math.Round(finite(watts) * 1000)
+	return int32(max(min(mw, math.MaxInt32), math.MinInt32))
+}
+
+// clampInt limits v to [lo, hi].
+func clampInt(v, lo, hi int) int {
+	return max(min(v, hi), lo)
+}
@@ -0,0 +1,165 @@
+package power
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// fieldDescriptions documents BatteryInfo's fields, including their units,
+// for JSONSchema. Keys are "Struct.Field". Fields without an entry are
+// emitted without a description. An entry replaces the description of the
+// field's type, so time.Duration fields must say they are in nanoseconds.
+var fieldDescriptions = map[string]string{
+	"BatteryInfo.ReadAt":         "When the IOKit read completed.",
+	"BatteryInfo.ReadLatency":    "How long the IOKit read took, in nanoseconds.",
+	"BatteryInfo.State":          "Booleans describing the current charging status.",
+	"BatteryInfo.Battery":        "Data points directly related to the battery itself.",
+	"BatteryInfo.Adapter":        "Information about the connected power source.",
+	"BatteryInfo.RecentAdapters": "Recently connected adapters, when the machine keeps that history.",
+	"BatteryInfo.Charger":        "Charging loop setpoints from ChargerData.",
+	"BatteryInfo.Calculations":   "Derived, user-friendly metrics.",
+	"BatteryInfo.Capabilities":   "Which optional data points this machine reported.",
+
+	"State.IsCharging":              "Whether the battery is currently charging.",
+	"State.IsConnected":             "Whether external power is connected.",
+	"State.FullyCharged":            "Whether the battery reports being fully charged.",
+	"State.OptimizedChargingActive": "Whether Optimized Battery Charging is holding the charge.",
+	"State.CalibrationInProgress":   "Whether charging appears held off for a gauge calibration; inferred, not reported.",
+	"State.BatteryInstalled":        "Whether a battery is installed.",
+	"State.AtCriticalLevel":         "Whether the battery is at a critically low level.",
+
+	"Battery.SerialNumber":            "Battery serial number.",
+	"Battery.DeviceName":              "Gas-gauge (BMS) chip name, e.g. bq40z651.",
+	"Battery.FirmwareVersion":         "Gas-gauge firmware version.",
+	"Battery.ChemID":                  "Cell chemistry identifier programmed into the gauge.",
+	"Battery.Manufacturer":            "Pack manufacturer code, e.g. SMP, when reported.",
+	"Battery.CycleCount":              "Charge cycle count.",
+	"Battery.PermanentFailureStatus":  "Non-zero when the gauge has latched a permanent failure.",
+	"Battery.GaugeStatus":             "Raw gauge status word; bit meanings vary by chip.",
+	"Battery.CalibrationNeeded":       "Whether the gauge requests a calibration cycle.",
+	"Battery.DesignCapacity":          "Design capacity in mAh.",
+	"Battery.MaxCapacity":             "Present full-charge capacity in mAh.",
+	"Battery.MaxCapacityFromPercent":  "Whether MaxCapacity was reported as a percentage and converted to mAh.",
+	"Battery.NominalCapacity":         "Nominal full-charge capacity in mAh.",
+	"Battery.CurrentCapacity":         "Present charge in mAh.",
+	"Battery.TimeToEmpty":             "Average time to empty in minutes.",
+	"Battery.TimeToFull":              "Average time to full in minutes.",
+	"Battery.Temperature":             "Battery temperature in Celsius.",
+	"Battery.Voltage":                 "Pack voltage in Volts.",
+	"Battery.Amperage":                "Pack current in Amps, negative when discharging.",
+	"Battery.IndividualCellVoltages":  "Per-cell voltages in mV.",
+	"Battery.CellVoltagesTruncated":   "Whether the gauge reported more cell voltages than were read.",
+	"Battery.TemperatureCentidegrees": "Battery temperature in hundredths of a degree Celsius.",
+	"Battery.VoltageMV":               "Raw pack voltage register in mV.",
+	"Battery.AmperageMA":              "Raw pack current register in mA, negative when discharging.",
+	"Battery.TimeSinceFullCharge":     "Time since the battery was last fully charged, in nanoseconds.",
+	"Battery.MaxChargeCurrent":        "Highest charge current recorded over the battery's lifetime in Amps.",
+	"Battery.MaxDischargeCurrent":     "Highest discharge current recorded over the battery's lifetime in Amps.",
+	"Battery.MaxChargePower":          "Upper-bound estimate of the most power the pack has accepted in Watts.",
+	"Battery.BatteryDataVoltage":      "Pack voltage reported inside BatteryData in Volts; may differ slightly from Voltage.",
+
+	"Adapter.Description":        "System-provided adapter description, e.g. pd charger.",
+	"Adapter.MaxWatts":           "Negotiated power rating in Watts.",
+	"Adapter.MaxVoltage":         "Negotiated voltage in Volts.",
+	"Adapter.MaxAmperage":        "Maximum current at the negotiated voltage in Amps.",
+	"Adapter.CableCurrentRating": "Cable e-marker current rating in Amps.",
+	"Adapter.Temperature":        "Adapter temperature in Celsius.",
+	"Adapter.InputVoltage":       "Voltage being supplied right now in Volts.",
+	"Adapter.InputAmperage":      "Current being drawn right now in Amps.",
+	"Adapter.IsWireless":         "Whether the power source is inductive (wireless).",
+	"Adapter.Role":               "Power direction at the port: sink, or empty without external power.",
+	"Adapter.PDRevision":         "USB Power Delivery revision of the contract, e.g. 3.1.",
+	"Adapter.SupportsPPS":        "Whether the adapter offers a Programmable Power Supply contract.",
+
+	"Charger.ChargingVoltage":   "Voltage the charger is targeting in Volts.",
+	"Charger.ChargingCurrent":   "Current the charger is allowing in Amps.",
+	"Charger.NotChargingReason": "Raw bitmask of reasons charging is inhibited; 0 when none.",
+	"Charger.VacVoltageLimit":   "Input voltage limit enforced on the adapter in Volts.",
+
+	"Calculations.HealthByMaxCapacity":          "MaxCapacity as a percentage of DesignCapacity.",
+	"Calculations.HealthByNominalCapacity":      "NominalCapacity as a percentage of DesignCapacity.",
+	"Calculations.ConditionAdjustedHealth":      "Nominal health adjusted for cell voltage drift.",
+	"Calculations.HealthByMaxCapacityFloat":     "HealthByMaxCapacity before rounding.",
+	"Calculations.HealthByNominalCapacityFloat": "HealthByNominalCapacity before rounding.",
+	"Calculations.CyclesRemaining":              "Cycles left before the rated cycle count; -1 when the rating is unknown.",
+	"Calculations.ACPower":                      "Power drawn from the adapter in Watts.",
+	"Calculations.BatteryPower":                 "Power into (+) or out of (-) the battery in Watts.",
+	"Calculations.SystemPower":                  "Power consumed by the rest of the system in Watts.",
+	"Calculations.SystemPowerAvailable":         "Whether SystemPower could be derived; false on AC without input telemetry.",
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing the JSON
+// encoding of BatteryInfo. It is derived from the Go types by reflection, so
+// it always matches what encoding/json produces for this version of the
+// package.
+func JSONSchema() ([]byte, error) {
+	schema := schemaFor(reflect.TypeOf(BatteryInfo{}))
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "BatteryInfo"
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaFor builds the schema for a single Go type.
+func schemaFor(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == durationType:
+		return map[string]any{"type": "integer", "description": "Duration in nanoseconds."}
+	case t.Implements(textMarshalerType):
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Pointer:
+		return schemaFor(t.Elem())
+	case reflect.Slice, reflect.Array:
+		return map[string]any{
+			"type":  []string{"array", "null"},
+			"items": schemaFor(t.Elem()),
+		}
+	case reflect.Map:
+		return map[string]any{
+			"type":                 []string{"object", "null"},
+			"additionalProperties": schemaFor(t.Elem()),
+		}
+	case reflect.Struct:
+		properties := map[string]any{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			property := schemaFor(field.Type)
+			if desc, ok := fieldDescriptions[t.Name()+"."+field.Name]; ok {
+				property["description"] = desc
+			}
+			properties[field.Name] = property
+			required = append(required, field.Name)
+		}
+		return map[string]any{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		}
+	}
+	return map[string]any{}
+}
@@ -0,0 +1,83 @@
+package power
+
+import (
+	"sync"
+	"time"
+)
+
+// stringCache holds the strings of a previous read, so that a read which
+// finds them unchanged can hand out the same Go strings instead of copying
+// them out of C memory again.
+type stringCache struct {
+	serialNumber       string
+	deviceName         string
+	firmwareVersion    string
+	manufacturer       string
+	adapterDescription string
+	pdRevision         string
+}
+
+// defaultSessionRefresh applies when NewSession is given a non-positive
+// refresh interval.
+const defaultSessionRefresh = time.Minute
+
+// Session reads snapshots like GetBatteryInfoWithOptions, but only re-reads
+// the identity strings (serial number, gauge name, firmware version,
+// manufacturer) every refresh interval. They don't change while the battery
+// is installed, so reads in between skip them in C as well as in Go and
+// reuse the strings of the last refresh, which removes most of the per-read
+// garbage of a high-frequency poller.
+//
+// The adapter strings (description and PD revision) are still read every
+// time, since they change as soon as a different adapter is connected, but
+// when a read finds them unchanged the previous Go strings are reused
+// instead of allocating new copies. Snapshots from a Session share the
+// backing memory of those strings, which is safe as Go strings are
+// immutable.
+//
+// A Session serializes its reads like a SafeReader and is safe for
+// concurrent use. It must not be copied after first use.
+type Session struct {
+	opts    Options
+	refresh time.Duration
+
+	mu         sync.Mutex
+	cache      stringCache
+	identityAt time.Time // when the identity strings were last read
+}
+
+// NewSession returns a Session that reads with opts and re-reads the
+// identity strings every refresh. A non-positive refresh means one minute.
+func NewSession(opts Options, refresh time.Duration) *Session {
+	if refresh <= 0 {
+		refresh = defaultSessionRefresh
+	}
+	return &Session{opts: opts, refresh: refresh}
+}
+
+// GetBatteryInfo reads a snapshot, reusing the Session's identity strings
+// between refreshes and unchanged adapter strings from its previous read.
+// Apart from identity strings up to one refresh interval old, it has the
+// same results as GetBatteryInfoWithOptions with the Session's Options.
+func (s *Session) GetBatteryInfo() (*BatteryInfo, error) {
+	sections := sectionAll
+	if s.opts.SkipNested {
+		sections &^= sectionNested
+	}
+
+	s.mu.Lock()
+	refresh := s.identityAt.IsZero() || time.Since(s.identityAt) >= s.refresh
+	if !refresh {
+		sections &^= sectionIdentity
+	}
+	info, err := readBatteryInfo(s.opts, sections, &s.cache)
+	if info != nil && refresh {
+		s.identityAt = info.ReadAt
+	}
+	s.mu.Unlock()
+	if info == nil {
+		return nil, err
+	}
+	calculateDerivedMetrics(info, s.opts)
+	return info, err
+}
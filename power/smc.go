@@ -0,0 +1,65 @@
+package power
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+)
+
+// smcKeySystemPower is the SMC key holding the total power drawn by the
+// machine, in Watts.
+const smcKeySystemPower = "PSTR"
+
+// smcErrType is the smcError code for a value whose data type smcValue
+// can't decode; the other codes come from smc_read_key.
+const smcErrType = 6
+
+// smcError is a failed SMC read.
+type smcError struct {
+	key  string
+	code int
+}
+
+func (e *smcError) Error() string {
+	return "power: SMC read of " + e.key + " failed with C error code: " + strconv.Itoa(e.code)
+}
+
+// smcValue decodes an SMC value of the given four-character data type. It
+// knows the numeric types SMC power keys use: "flt " (a little-endian
+// float32, on Apple Silicon), the big-endian fixed-point "spXY" (signed) and
+// "fpXY" (unsigned) types, whose last hex digit is the number of fraction
+// bits, and the big-endian integers "ui8 ", "ui16", "ui32", "si8 " and
+// "si16". ok is false for any other type or a value of the wrong size.
+func smcValue(dataType string, data []byte) (value float64, ok bool) {
+	be := binary.BigEndian
+	switch {
+	case dataType == "flt " && len(data) == 4:
+		value = float64(math.Float32frombits(binary.LittleEndian.Uint32(data)))
+	case len(dataType) == 4 && (dataType[:2] == "sp" || dataType[:2] == "fp") && len(data) == 2:
+		bits, err := strconv.ParseUint(dataType[3:], 16, 8)
+		if err != nil {
+			return 0, false
+		}
+		raw := float64(be.Uint16(data))
+		if dataType[0] == 's' {
+			raw = float64(int16(be.Uint16(data)))
+		}
+		value = raw / float64(uint(1)<<bits)
+	case dataType == "ui8 " && len(data) == 1:
+		value = float64(data[0])
+	case dataType == "ui16" && len(data) == 2:
+		value = float64(be.Uint16(data))
+	case dataType == "ui32" && len(data) == 4:
+		value = float64(be.Uint32(data))
+	case dataType == "si8 " && len(data) == 1:
+		value = float64(int8(data[0]))
+	case dataType == "si16" && len(data) == 2:
+		value = float64(int16(be.Uint16(data)))
+	default:
+		return 0, false
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, false
+	}
+	return value, true
+}
@@ -0,0 +1,124 @@
+package power
+
+/*
+#cgo LDFLAGS: -framework IOKit
+
+#include <stdlib.h>
+#include <string.h>
+#include <mach/mach.h>
+#include <IOKit/IOKitLib.h>
+
+// The AppleSMC user client method, and the commands passed through it, that
+// every SMC tool uses.
+#define SMC_HANDLE_YPC_EVENT 2
+#define SMC_CMD_READ_BYTES   5
+#define SMC_CMD_READ_KEYINFO 9
+
+typedef struct {
+    unsigned char major, minor, build, reserved;
+    unsigned short release;
+} smc_version;
+
+typedef struct {
+    unsigned short version;
+    unsigned short length;
+    unsigned int cpu_limit;
+    unsigned int gpu_limit;
+    unsigned int mem_limit;
+} smc_plimit;
+
+typedef struct {
+    unsigned int data_size;
+    unsigned int data_type;
+    unsigned char data_attributes;
+} smc_key_info;
+
+// The structure exchanged with AppleSMC. Its layout is fixed by the driver.
+typedef struct {
+    unsigned int key;
+    smc_version vers;
+    smc_plimit plimit;
+    smc_key_info key_info;
+    unsigned char result;
+    unsigned char status;
+    unsigned char data8;
+    unsigned int data32;
+    unsigned char bytes[32];
+} smc_param;
+
+_Static_assert(sizeof(smc_param) == 80, "smc_param must match the AppleSMC layout");
+
+// Return codes of smc_read_key.
+#define SMC_ERR_NO_SERVICE 1
+#define SMC_ERR_OPEN       2
+#define SMC_ERR_CALL       3
+#define SMC_ERR_KEY        4 // the SMC rejected the key, e.g. it doesn't exist
+#define SMC_ERR_SIZE       5
+
+static int smc_call(io_connect_t conn, smc_param *in, smc_param *out) {
+    size_t out_size = sizeof(*out);
+    memset(out, 0, sizeof(*out));
+    if (IOConnectCallStructMethod(conn, SMC_HANDLE_YPC_EVENT, in, sizeof(*in), out, &out_size) != KERN_SUCCESS) {
+        return SMC_ERR_CALL;
+    }
+    return out->result == 0 ? 0 : SMC_ERR_KEY;
+}
+
+// Reads the four-character key from the SMC into bytes (32 bytes), setting
+// *size to the value's length and *type to its four-character data type.
+// Returns 0 on success or an SMC_ERR_* code.
+static int smc_read_key(const char *key, unsigned char *bytes, unsigned int *size, char *type) {
+    // IOServiceGetMatchingService consumes the matching dictionary.
+    io_service_t smc = IOServiceGetMatchingService(kIOMainPortDefault, IOServiceMatching("AppleSMC"));
+    if (smc == IO_OBJECT_NULL) return SMC_ERR_NO_SERVICE;
+
+    io_connect_t conn;
+    kern_return_t kr = IOServiceOpen(smc, mach_task_self(), 0, &conn);
+    IOObjectRelease(smc);
+    if (kr != KERN_SUCCESS) return SMC_ERR_OPEN;
+
+    smc_param in, out;
+    memset(&in, 0, sizeof(in));
+    in.key = (unsigned int)key[0] << 24 | (unsigned int)key[1] << 16 | (unsigned int)key[2] << 8 | (unsigned int)key[3];
+    in.data8 = SMC_CMD_READ_KEYINFO;
+    int err = smc_call(conn, &in, &out);
+    if (err == 0 && out.key_info.data_size > sizeof(out.bytes)) err = SMC_ERR_SIZE;
+    if (err == 0) {
+        *size = out.key_info.data_size;
+        unsigned int t = out.key_info.data_type;
+        type[0] = (char)(t >> 24);
+        type[1] = (char)(t >> 16);
+        type[2] = (char)(t >> 8);
+        type[3] = (char)t;
+
+        in.key_info.data_size = *size;
+        in.data8 = SMC_CMD_READ_BYTES;
+        err = smc_call(conn, &in, &out);
+        if (err == 0) memcpy(bytes, out.bytes, *size);
+    }
+    IOServiceClose(conn);
+    return err;
+}
+*/
+import "C"
+import "unsafe"
+
+// readSMCSystemPower reads the total system power, in Watts, from the SMC.
+func readSMCSystemPower() (float64, error) {
+	key := C.CString(smcKeySystemPower)
+	defer C.free(unsafe.Pointer(key))
+
+	var (
+		data     [32]byte
+		size     C.uint
+		dataType [4]C.char
+	)
+	if ret := C.smc_read_key(key, (*C.uchar)(unsafe.Pointer(&data[0])), &size, &dataType[0]); ret != 0 {
+		return 0, &smcError{key: smcKeySystemPower, code: int(ret)}
+	}
+	value, ok := smcValue(C.GoStringN(&dataType[0], 4), data[:size])
+	if !ok {
+		return 0, &smcError{key: smcKeySystemPower, code: smcErrType}
+	}
+	return value, nil
+}
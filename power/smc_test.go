@@ -0,0 +1,43 @@
+package power
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestSMCValue(t *testing.T) {
+	flt := func(f float32) []byte {
+		return binary.LittleEndian.AppendUint32(nil, math.Float32bits(f))
+	}
+	tests := []struct {
+		dataType string
+		data     []byte
+		want     float64
+		ok       bool
+	}{
+		{"flt ", flt(14.25), 14.25, true},
+		{"flt ", flt(float32(math.NaN())), 0, false},
+		{"flt ", []byte{0, 0}, 0, false},
+		{"sp78", []byte{0x1e, 0x80}, 30.5, true},
+		{"sp78", []byte{0xff, 0x00}, -1, true},
+		{"sp96", []byte{0x03, 0x20}, 12.5, true},
+		{"spb4", []byte{0x00, 0x18}, 1.5, true},
+		{"fpe2", []byte{0x00, 0x0a}, 2.5, true},
+		{"fp88", []byte{0x80, 0x80}, 128.5, true},
+		{"ui8 ", []byte{42}, 42, true},
+		{"ui16", []byte{0x01, 0x00}, 256, true},
+		{"ui32", []byte{0, 1, 0, 0}, 65536, true},
+		{"si8 ", []byte{0xfe}, -2, true},
+		{"si16", []byte{0xff, 0xfe}, -2, true},
+		{"ui16", []byte{1}, 0, false},
+		{"spzz", []byte{0, 0}, 0, false},
+		{"ch8*", []byte("abcd"), 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := smcValue(tt.dataType, tt.data)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("smcValue(%q, % x) = %v, %v, want %v, %v", tt.dataType, tt.data, got, ok, tt.want, tt.ok)
+		}
+	}
+}
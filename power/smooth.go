@@ -0,0 +1,114 @@
+package power
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// smoothedFieldCount is the number of fields PowerSmoother averages.
+const smoothedFieldCount = 8
+
+// smoothedFields returns pointers to the live electrical and thermal readings
+// that PowerSmoother averages. Identity, capacity and state fields are not
+// included and pass through unchanged.
+func smoothedFields(b *BatteryInfo) [smoothedFieldCount]*float64 {
+	return [smoothedFieldCount]*float64{
+		&b.Battery.Temperature,
+		&b.Battery.Voltage,
+		&b.Battery.Amperage,
+		&b.Adapter.InputVoltage,
+		&b.Adapter.InputAmperage,
+		&b.Calculations.ACPower,
+		&b.Calculations.BatteryPower,
+		&b.Calculations.SystemPower,
+	}
+}
+
+// PowerSmoother applies a simple moving average over the last N snapshots to
+// the power, voltage, current and temperature fields. It is not safe for
+// concurrent use.
+type PowerSmoother struct {
+	window  int
+	samples [][smoothedFieldCount]float64 // ring buffer of raw readings
+	next    int
+}
+
+// NewPowerSmoother returns a smoother averaging over the given number of
+// samples. A window below 1 is treated as 1 (no smoothing).
+func NewPowerSmoother(window int) *PowerSmoother {
+	return &PowerSmoother{window: max(window, 1)}
+}
+
+// Add records a snapshot and returns a copy of it whose smoothed fields hold
+// the average over the window so far. The input is not modified.
+func (s *PowerSmoother) Add(info *BatteryInfo) *BatteryInfo {
+	if info == nil {
+		return nil
+	}
+	out := info.clone()
+	fields := smoothedFields(out)
+
+	var raw [smoothedFieldCount]float64
+	for i, f := range fields {
+		raw[i] = *f
+	}
+	if len(s.samples) < s.window {
+		s.samples = append(s.samples, raw)
+	} else {
+		s.samples[s.next] = raw
+	}
+	s.next = (s.next + 1) % s.window
+
+	for i, f := range fields {
+		var sum float64
+		for _, sample := range s.samples {
+			sum += sample[i]
+		}
+		*f = sum / float64(len(s.samples))
+	}
+	// Keep the integer temperature in step with the smoothed float.
+	out.Battery.TemperatureCentidegrees = int(math.Round(out.Battery.Temperature * 100))
+	return out
+}
+
+// Reset discards the samples in the window, keeping its storage, so that the
+// next Add starts averaging afresh.
+func (s *PowerSmoother) Reset() {
+	s.samples = s.samples[:0]
+	s.next = 0
+}
+
+// WatchSmoothed polls GetBatteryInfo every interval and emits snapshots whose
+// power, voltage, current and temperature fields are averaged over the last
+// window readings (see PowerSmoother). The first snapshot is read
+// immediately, and a non-positive interval means five seconds, as with
+// Watch. Failed reads are skipped. The channel is closed once ctx is done.
+func WatchSmoothed(ctx context.Context, interval time.Duration, window int) <-chan *BatteryInfo {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	out := make(chan *BatteryInfo)
+	go func() {
+		defer close(out)
+		smoother := NewPowerSmoother(window)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if info, err := GetBatteryInfo(); err == nil {
+				select {
+				case out <- smoother.Add(info):
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return out
+}
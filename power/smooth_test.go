@@ -0,0 +1,17 @@
+package power
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWatchSmoothedNonPositiveInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		for range WatchSmoothed(ctx, interval, 3) {
+			cancel()
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,23 @@
+package power
+
+// clone returns a deep copy of the snapshot, so that callers handed a shared
+// snapshot cannot observe each other's modifications.
+func (b *BatteryInfo) clone() *BatteryInfo {
+	if b == nil {
+		return nil
+	}
+	c := *b
+	if b.Battery.IndividualCellVoltages != nil {
+		c.Battery.IndividualCellVoltages = append([]int(nil), b.Battery.IndividualCellVoltages...)
+	}
+	if b.RecentAdapters != nil {
+		c.RecentAdapters = append([]Adapter(nil), b.RecentAdapters...)
+	}
+	if b.Capabilities != nil {
+		c.Capabilities = make(map[Capability]bool, len(b.Capabilities))
+		for k, v := range b.Capabilities {
+			c.Capabilities[k] = v
+		}
+	}
+	return &c
+}
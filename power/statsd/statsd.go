@@ -0,0 +1,71 @@
+// Package statsd pushes power telemetry to a statsd server through a client
+// the caller provides.
+package statsd
+
+import (
+	"errors"
+
+	"github.com/peterneutron/go-iokit-powertelemetry/power"
+)
+
+// StatsdClient is the part of a statsd client PushStatsd needs. Most statsd
+// libraries satisfy it directly or with a one-line adapter.
+type StatsdClient interface {
+	// Gauge records the current value of the named gauge.
+	Gauge(name string, value float64) error
+}
+
+// PushStatsd emits the key metrics of a snapshot as gauges. Names are the
+// prefix followed by a dotted, snake_case path, for example
+// "<prefix>.battery.cycle_count" or "<prefix>.power.system_watts"; an empty
+// prefix leaves the leading dot off. Booleans are sent as 0 or 1. Every
+// gauge is attempted, and the errors of any that failed are returned
+// together.
+func PushStatsd(client StatsdClient, prefix string, info *power.BatteryInfo) error {
+	if info == nil {
+		return nil
+	}
+	if prefix != "" {
+		prefix += "."
+	}
+	gauges := []struct {
+		name  string
+		value float64
+	}{
+		{"state.charging", boolValue(info.State.IsCharging)},
+		{"state.connected", boolValue(info.State.IsConnected)},
+		{"state.fully_charged", boolValue(info.State.FullyCharged)},
+		{"battery.charge_fraction", info.ChargeFraction()},
+		{"battery.cycle_count", float64(info.Battery.CycleCount)},
+		{"battery.design_capacity_mah", float64(info.Battery.DesignCapacity)},
+		{"battery.max_capacity_mah", float64(info.Battery.MaxCapacity)},
+		{"battery.current_capacity_mah", float64(info.Battery.CurrentCapacity)},
+		{"battery.temperature_celsius", info.Battery.Temperature},
+		{"battery.voltage_volts", info.Battery.Voltage},
+		{"battery.amperage_amps", info.Battery.Amperage},
+		{"adapter.max_watts", float64(info.Adapter.MaxWatts)},
+		{"adapter.input_voltage_volts", info.Adapter.InputVoltage},
+		{"adapter.input_amperage_amps", info.Adapter.InputAmperage},
+		{"health.max_capacity_percent", info.Calculations.HealthByMaxCapacityFloat},
+		{"health.nominal_capacity_percent", info.Calculations.HealthByNominalCapacityFloat},
+		{"health.condition_adjusted_percent", float64(info.Calculations.ConditionAdjustedHealth)},
+		{"power.ac_watts", info.Calculations.ACPower},
+		{"power.battery_watts", info.Calculations.BatteryPower},
+		{"power.system_watts", info.Calculations.SystemPower},
+	}
+
+	var errs []error
+	for _, g := range gauges {
+		if err := client.Gauge(prefix+g.name, g.value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,824 @@
+package power
+
+/*
+#cgo LDFLAGS: -framework CoreFoundation -framework IOKit
+
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/IOKitLib.h>
+
+#include "cfhelpers.h"
+
+// Most cell voltages read per pack. Current packs have at most a handful of
+// cell blocks; a pack reporting more is truncated, see cell_voltage_total.
+// Must match maxCellVoltages on the Go side.
+#define MAX_CELLS 16
+
+// Most entries read from AppleRawAdapterDetails.
+#define MAX_RECENT_ADAPTERS 8
+
+// One entry of the AppleRawAdapterDetails history.
+typedef struct {
+    long watts;
+    long voltage;  // mV
+    long amperage; // mA
+    int  is_wireless;
+    char *description;
+} c_adapter;
+
+// C-side struct to hold the raw data. We use this as an intermediary
+// to avoid passing complex Go pointers into C.
+typedef struct {
+    // Top-level booleans
+    int is_charging;
+    int is_connected;
+    int is_fully_charged;
+    int is_optimized_charging;
+
+    // Manager-level flags (merged from the AppleSmartBatteryManager parent)
+    int is_battery_installed;
+    int is_at_critical_level;
+
+    // Health
+    long cycle_count;
+    long permanent_failure_status;
+    long gauge_status;
+
+    // Capacity (mAh)
+    long design_capacity;
+    long max_capacity;
+    long nominal_capacity;
+
+    // Charge (mAh)
+    long current_capacity;
+    long time_to_empty;
+    long time_to_full;
+    long time_since_full_charge; // seconds
+
+    // Temperature (°C * 100)
+    long temperature;
+
+    // Power (mV, mA)
+    long voltage;
+    long amperage;
+
+    // Hardware strings (malloc'd, freed by free_battery_info)
+    char *serial_number;
+    char *device_name;
+    char *firmware_version;
+    char *manufacturer;
+    long gas_gauge_firmware_version;
+    long chem_id;
+
+    // Adapter Info
+    long adapter_watts;
+    long adapter_voltage;
+    long adapter_amperage;
+    long adapter_cable_current;
+    long adapter_temperature; // °C * 100
+    char *adapter_description;
+    int  adapter_is_wireless;
+    int  adapter_sinking;
+    char *adapter_pd_revision;
+    int  adapter_supports_pps;
+
+    // Recently connected adapters
+    c_adapter recent_adapters[MAX_RECENT_ADAPTERS];
+    int recent_adapter_count;
+
+    // Power Source Input (mV, mA)
+    long source_voltage;
+    long source_amperage;
+
+    // Charger setpoints (mV, mA)
+    long charging_voltage;
+    long charging_current;
+    long not_charging_reason;
+    long vac_voltage_limit;
+
+	// Cell Voltages
+    long cell_voltages[MAX_CELLS];
+    int  cell_voltage_count;
+    long cell_voltage_total; // as reported, before capping at MAX_CELLS
+
+    // Highest charge and discharge currents the gauge has recorded (mA)
+    long max_charge_current;
+    long max_discharge_current;
+
+    // Pack voltage as reported inside BatteryData (mV)
+    long battery_data_voltage;
+
+    // Presence flags for optional keys (see Capabilities)
+    int has_adapter_cable_current;
+    int has_adapter_temperature;
+    int has_optimized_charging;
+    int has_gauge_status;
+    int has_charger_data;
+    int has_max_charge_current;
+    int has_max_discharge_current;
+    int has_time_since_full_charge;
+    int has_adapter_is_wireless;
+    int has_adapter_pps;
+    int has_battery_data_voltage;
+
+    // Expected keys that were absent, as MISSING_* bits (see Options.Strict)
+    long missing;
+
+} c_battery_info;
+
+// Expected keys and dictionaries that every battery should report. Bits are
+// indexed by the strictKeys table on the Go side; keep them in sync.
+#define MISSING_BATTERY_DATA       (1 << 0)
+#define MISSING_ADAPTER_DETAILS    (1 << 1)
+#define MISSING_POWER_TELEMETRY    (1 << 2)
+#define MISSING_CYCLE_COUNT        (1 << 3)
+#define MISSING_DESIGN_CAPACITY    (1 << 4)
+#define MISSING_MAX_CAPACITY       (1 << 5)
+#define MISSING_CURRENT_CAPACITY   (1 << 6)
+#define MISSING_VOLTAGE            (1 << 7)
+#define MISSING_AMPERAGE           (1 << 8)
+
+// Records bit in info->missing if dict lacks key.
+static void note_missing(c_battery_info *info, CFDictionaryRef dict, const char *key, long bit) {
+    if (!has_prop(dict, key)) info->missing |= bit;
+}
+
+// Stores value in *field, freeing the string it held, so that a section can
+// be decoded into the same struct more than once.
+static void replace_string(char **field, char *value) {
+    free(*field);
+    *field = value;
+}
+
+// Releases the strings get_battery_info allocated. Safe to call on a struct
+// that was zeroed and only partly populated.
+static void free_battery_info(c_battery_info *info) {
+    free(info->serial_number);
+    free(info->device_name);
+    free(info->firmware_version);
+    free(info->manufacturer);
+    free(info->adapter_description);
+    free(info->adapter_pd_revision);
+    for (int i = 0; i < info->recent_adapter_count; i++) {
+        free(info->recent_adapters[i].description);
+    }
+}
+
+// Sections of the snapshot get_battery_info can populate. Must match the
+// readSections constants on the Go side.
+#define SECTION_STATE   (1 << 0)
+#define SECTION_BATTERY (1 << 1)
+#define SECTION_ADAPTER (1 << 2)
+#define SECTION_CHARGER (1 << 3)
+// Follow the nested dictionaries (BatteryData, AdapterDetails,
+// PowerTelemetryData, ChargerData) of the other sections.
+#define SECTION_NESTED  (1 << 4)
+// The battery's identity strings: serial number, device name, firmware
+// version and manufacturer.
+#define SECTION_IDENTITY (1 << 5)
+
+// get_dict_prop, unless the nested dictionaries are being skipped.
+static CFDictionaryRef get_nested_prop(CFDictionaryRef dict, const char *key, int sections) {
+    if (!(sections & SECTION_NESTED)) return NULL;
+    return get_dict_prop(dict, key);
+}
+
+// The AppleSmartBattery properties, and those of its AppleSmartBatteryManager
+// parent, as copied from the registry by copy_service_properties. The
+// dictionaries are opaque pointers so Go never has to handle the CF
+// reference types directly.
+typedef struct {
+    void *properties;
+    void *manager_properties; // NULL when the parent wasn't read
+} c_battery_props;
+
+// Returns the AppleSmartBattery service, which the caller must release, or
+// IO_OBJECT_NULL if there is none. Sets *error to the get_battery_info
+// return code on failure.
+static io_service_t match_battery_service(int *error) {
+    CFMutableDictionaryRef matching = IOServiceMatching("AppleSmartBattery");
+    if (matching == NULL) { *error = 1; return IO_OBJECT_NULL; }
+
+    io_iterator_t iterator;
+
+	// IOServiceGetMatchingServices always consumes the 'matching' dictionary reference.
+    if (IOServiceGetMatchingServices(kIOMainPortDefault, matching, &iterator) != KERN_SUCCESS) {
+        *error = 2;
+        return IO_OBJECT_NULL;
+    }
+
+    io_service_t battery = IOIteratorNext(iterator);
+    IOObjectRelease(iterator);
+    if (battery == IO_OBJECT_NULL) *error = 3;
+    return battery;
+}
+
+// Copies the properties of battery into props, and those of its parent too
+// when sections include SECTION_STATE. The service reference is not
+// consumed. Returns 0 on success, after which the caller must call
+// release_battery_properties, or non-zero on error.
+static int copy_service_properties(io_service_t battery, c_battery_props *props, int sections) {
+    CFMutableDictionaryRef properties = NULL;
+    if (IORegistryEntryCreateCFProperties(battery, &properties, kCFAllocatorDefault, 0) != KERN_SUCCESS ||
+        properties == NULL) {
+        return 4;
+    }
+    props->properties = (void *)properties;
+
+    // Some flags live on the AppleSmartBatteryManager parent rather than on
+    // the battery itself. A missing parent is not an error.
+    io_registry_entry_t manager = IO_OBJECT_NULL;
+    if ((sections & SECTION_STATE) &&
+        IORegistryEntryGetParentEntry(battery, kIOServicePlane, &manager) == KERN_SUCCESS) {
+        CFMutableDictionaryRef manager_properties = NULL;
+        if (IORegistryEntryCreateCFProperties(manager, &manager_properties, kCFAllocatorDefault, 0) == KERN_SUCCESS) {
+            props->manager_properties = (void *)manager_properties;
+        }
+        IOObjectRelease(manager); // GetParentEntry returns a reference we own
+    }
+    return 0;
+}
+
+// Releases the dictionaries copy_service_properties copied.
+static void release_battery_properties(c_battery_props *props) {
+    if (props->properties) CFRelease((CFTypeRef)props->properties);
+    if (props->manager_properties) CFRelease((CFTypeRef)props->manager_properties);
+    props->properties = NULL;
+    props->manager_properties = NULL;
+}
+
+// Property key literals below are mirrored as Key* constants in keys.go.
+
+// Parses the requested sections of props into info; fields of other sections
+// are left untouched, and decoding a section again overwrites its fields.
+// The dictionaries are only read, never retained.
+static void decode_battery_info(c_battery_info *info, const c_battery_props *props, int sections) {
+    CFDictionaryRef properties = (CFDictionaryRef)props->properties;
+    CFDictionaryRef manager_properties = (CFDictionaryRef)props->manager_properties;
+
+    // --- Populate the struct using our safe helpers ---
+
+    if (sections & SECTION_STATE) {
+        info->is_charging = get_bool_prop(properties, "IsCharging");
+        info->is_connected = get_bool_prop(properties, "ExternalConnected");
+        info->is_fully_charged = get_bool_prop(properties, "FullyCharged");
+
+        // Manager-level flags may be reported by either entry.
+        info->is_battery_installed = get_bool_prop(properties, "BatteryInstalled");
+        info->is_at_critical_level = get_bool_prop(properties, "AtCriticalLevel");
+        if (manager_properties) {
+            info->is_battery_installed |= get_bool_prop(manager_properties, "BatteryInstalled");
+            info->is_at_critical_level |= get_bool_prop(manager_properties, "AtCriticalLevel");
+        }
+
+        // Optimized Battery Charging is reported at the top level on some
+        // systems and inside ChargerData on others.
+        CFDictionaryRef charger_data = get_nested_prop(properties, "ChargerData", sections);
+        if (has_prop(properties, "OptimizedBatteryChargingEngaged")) {
+            info->is_optimized_charging = get_bool_prop(properties, "OptimizedBatteryChargingEngaged");
+            info->has_optimized_charging = 1;
+        } else if (charger_data && has_prop(charger_data, "OptimizedBatteryChargingEngaged")) {
+            info->is_optimized_charging = get_bool_prop(charger_data, "OptimizedBatteryChargingEngaged");
+            info->has_optimized_charging = 1;
+        }
+    }
+
+    if (sections & SECTION_BATTERY) {
+        note_missing(info, properties, "CycleCount", MISSING_CYCLE_COUNT);
+        note_missing(info, properties, "DesignCapacity", MISSING_DESIGN_CAPACITY);
+        if (!has_prop(properties, "AppleRawMaxCapacity")) {
+            note_missing(info, properties, "MaxCapacity", MISSING_MAX_CAPACITY);
+        }
+        note_missing(info, properties, "AppleRawCurrentCapacity", MISSING_CURRENT_CAPACITY);
+        note_missing(info, properties, "Voltage", MISSING_VOLTAGE);
+        note_missing(info, properties, "Amperage", MISSING_AMPERAGE);
+
+        info->cycle_count = get_long_prop(properties, "CycleCount");
+        info->permanent_failure_status = get_long_prop(properties, "PermanentFailureStatus");
+
+        info->design_capacity = get_long_prop(properties, "DesignCapacity");
+        info->max_capacity = get_long_prop(properties, "AppleRawMaxCapacity");
+        // Some Intel models only report MaxCapacity, occasionally as a
+        // percentage; see Battery.MaxCapacityFromPercent.
+        if (!has_prop(properties, "AppleRawMaxCapacity")) {
+            info->max_capacity = get_long_prop(properties, "MaxCapacity");
+        }
+        info->nominal_capacity = get_long_prop(properties, "NominalChargeCapacity");
+
+        info->current_capacity = get_long_prop(properties, "AppleRawCurrentCapacity");
+        info->time_to_empty = get_long_prop(properties, "AvgTimeToEmpty");
+        info->time_to_full = get_long_prop(properties, "AvgTimeToFull");
+        // Usage statistic only some models report.
+        info->time_since_full_charge = get_long_prop(properties, "TimeSinceLastFullCharge");
+        info->has_time_since_full_charge = has_prop(properties, "TimeSinceLastFullCharge");
+
+        info->temperature = get_long_prop(properties, "Temperature");
+
+        info->voltage = get_long_prop(properties, "Voltage");
+        info->amperage = get_long_prop(properties, "Amperage");
+
+        info->chem_id = get_long_prop(properties, "ChemID");
+
+        // Get cell voltages from the nested BatteryData dictionary ---
+        CFDictionaryRef battery_data = get_nested_prop(properties, "BatteryData", sections);
+        if (!battery_data && (sections & SECTION_NESTED)) info->missing |= MISSING_BATTERY_DATA;
+        if (battery_data) {
+            // We know CellVoltage is inside BatteryData
+            get_long_array_prop(battery_data, "CellVoltage", info->cell_voltages, MAX_CELLS, &info->cell_voltage_count);
+            info->cell_voltage_total = get_array_count(battery_data, "CellVoltage");
+
+            // The gauge's own pack voltage reading; see Battery.BatteryDataVoltage.
+            info->battery_data_voltage = get_long_prop(battery_data, "Voltage");
+            info->has_battery_data_voltage = has_prop(battery_data, "Voltage");
+
+            // Raw gauge status word; see Battery.GaugeStatus.
+            info->gauge_status = get_long_prop(battery_data, "GaugeFlagRaw");
+            info->has_gauge_status = has_prop(battery_data, "GaugeFlagRaw");
+
+            // Some gauges only report the chemistry ID inside BatteryData.
+            if (info->chem_id == 0) {
+                info->chem_id = get_long_prop(battery_data, "ChemID");
+            }
+
+            // Lifetime extremes recorded by the gauge.
+            CFDictionaryRef lifetime_data = get_dict_prop(battery_data, "LifetimeData");
+            if (lifetime_data) {
+                info->max_charge_current = get_long_prop(lifetime_data, "MaximumChargeCurrent");
+                info->has_max_charge_current = has_prop(lifetime_data, "MaximumChargeCurrent");
+                info->max_discharge_current = get_long_prop(lifetime_data, "MaximumDischargeCurrent");
+                info->has_max_discharge_current = has_prop(lifetime_data, "MaximumDischargeCurrent");
+            }
+        }
+    }
+
+    if (sections & SECTION_IDENTITY) {
+        replace_string(&info->serial_number, copy_string_prop(properties, "Serial"));
+        replace_string(&info->device_name, copy_string_prop(properties, "DeviceName"));
+        replace_string(&info->firmware_version, copy_string_prop(properties, "FirmwareVersion"));
+        replace_string(&info->manufacturer, copy_string_prop(properties, "Manufacturer"));
+        info->gas_gauge_firmware_version = get_long_prop(properties, "GasGaugeFirmwareVersion");
+    }
+
+    if (sections & SECTION_ADAPTER) {
+        // The battery only reports power flowing into the machine.
+        info->adapter_sinking = get_bool_prop(properties, "ExternalConnected");
+
+        // Get nested adapter info
+        CFDictionaryRef adapter_details = get_nested_prop(properties, "AdapterDetails", sections);
+        if (adapter_details) {
+            info->adapter_watts = get_long_prop(adapter_details, "Watts");
+            info->adapter_voltage = get_long_prop(adapter_details, "AdapterVoltage");
+            info->adapter_amperage = get_long_prop(adapter_details, "Current");
+            // Only reported by some PD adapters, from the cable's e-marker.
+            info->adapter_cable_current = get_long_prop(adapter_details, "CableCurrent");
+            info->has_adapter_cable_current = has_prop(adapter_details, "CableCurrent");
+            // Only reported by some high-wattage adapters.
+            info->adapter_temperature = get_long_prop(adapter_details, "AdapterTemperature");
+            info->has_adapter_temperature = has_prop(adapter_details, "AdapterTemperature");
+            replace_string(&info->adapter_description, copy_string_prop(adapter_details, "Description"));
+            // Only reported by adapters that can tell wired from inductive.
+            info->adapter_is_wireless = get_bool_prop(adapter_details, "IsWireless");
+            info->has_adapter_is_wireless = has_prop(adapter_details, "IsWireless");
+            // USB PD contract details, only reported by some adapter and
+            // machine combinations.
+            replace_string(&info->adapter_pd_revision, copy_string_prop(adapter_details, "PDRevision"));
+            info->adapter_supports_pps = get_bool_prop(adapter_details, "SupportsPPS");
+            info->has_adapter_pps = has_prop(adapter_details, "SupportsPPS");
+        }
+
+        // Some machines keep the details of recently connected adapters.
+        for (int i = 0; i < info->recent_adapter_count; i++) {
+            free(info->recent_adapters[i].description);
+        }
+        info->recent_adapter_count = 0;
+        CFArrayRef raw_adapters = (sections & SECTION_NESTED) ? get_array_prop(properties, "AppleRawAdapterDetails") : NULL;
+        if (raw_adapters) {
+            CFIndex count = CFArrayGetCount(raw_adapters);
+            for (CFIndex i = 0; i < count && info->recent_adapter_count < MAX_RECENT_ADAPTERS; i++) {
+                CFDictionaryRef details = (CFDictionaryRef)CFArrayGetValueAtIndex(raw_adapters, i);
+                if (details == NULL || CFGetTypeID(details) != CFDictionaryGetTypeID()) continue;
+
+                c_adapter *a = &info->recent_adapters[info->recent_adapter_count++];
+                a->watts = get_long_prop(details, "Watts");
+                a->voltage = get_long_prop(details, "AdapterVoltage");
+                a->amperage = get_long_prop(details, "Current");
+                a->is_wireless = get_bool_prop(details, "IsWireless");
+                a->description = copy_string_prop(details, "Description");
+            }
+        }
+
+        // Get nested power source input info
+        CFDictionaryRef power_telemetry = get_nested_prop(properties, "PowerTelemetryData", sections);
+
+        // Adapter dictionaries are only expected while external power is
+        // connected.
+        if ((sections & SECTION_NESTED) && get_bool_prop(properties, "ExternalConnected")) {
+            if (!adapter_details) info->missing |= MISSING_ADAPTER_DETAILS;
+            if (!power_telemetry) info->missing |= MISSING_POWER_TELEMETRY;
+        }
+        if (power_telemetry) {
+            info->source_voltage = get_long_prop(power_telemetry, "SystemVoltageIn");
+            info->source_amperage = get_long_prop(power_telemetry, "SystemCurrentIn");
+        }
+    }
+
+    if (sections & SECTION_CHARGER) {
+        // Charging loop setpoints, distinct from the adapter's negotiated
+        // maximums in AdapterDetails. Only reported on Apple Silicon.
+        CFDictionaryRef charger_data = get_nested_prop(properties, "ChargerData", sections);
+        if (charger_data) {
+            info->charging_voltage = get_long_prop(charger_data, "ChargingVoltage");
+            info->charging_current = get_long_prop(charger_data, "ChargingCurrent");
+            info->not_charging_reason = get_long_prop(charger_data, "NotChargingReason");
+            info->vac_voltage_limit = get_long_prop(charger_data, "VacVoltageLimit");
+            info->has_charger_data = 1;
+        }
+    }
+
+}
+
+// Finds the AppleSmartBattery service and copies its properties, as
+// copy_service_properties does. Returns 0 on success, after which the caller
+// must call release_battery_properties, or non-zero on error.
+static int copy_battery_properties(c_battery_props *props, int sections) {
+    int error = 0;
+    io_service_t battery = match_battery_service(&error);
+    if (battery == IO_OBJECT_NULL) return error;
+
+    error = copy_service_properties(battery, props, sections);
+    IOObjectRelease(battery); // Done with the service object
+    return error;
+}
+
+// The core C function to get battery properties. Only the requested sections
+// are parsed; fields of other sections are left untouched.
+// Returns 0 on success, non-zero on error.
+int get_battery_info(c_battery_info *info, int sections) {
+    c_battery_props props = {0};
+    int error = copy_battery_properties(&props, sections);
+    if (error != 0) return error;
+
+    decode_battery_info(info, &props, sections);
+    release_battery_properties(&props);
+    return 0; // Success
+}
+
+// Retain counts sampled by the leak test in leak_darwin_test.go. Test files
+// can't import "C", so the test drives these through retainProbe.
+typedef struct {
+    long service;      // user references to the service
+    long properties;   // references to the properties dictionary
+    long battery_data; // references to its BatteryData dictionary
+} c_retain_counts;
+
+static void sample_retain_counts(io_service_t battery, const c_battery_props *props, c_retain_counts *counts) {
+    CFDictionaryRef properties = (CFDictionaryRef)props->properties;
+    CFDictionaryRef battery_data = get_dict_prop(properties, "BatteryData");
+    counts->service = (long)IOObjectGetUserRetainCount(battery);
+    counts->properties = (long)CFGetRetainCount(properties);
+    counts->battery_data = battery_data ? (long)CFGetRetainCount(battery_data) : 0;
+}
+
+// Parses a plist of AppleSmartBattery properties into props, so decoder tests
+// can run on a fixture rather than the registry. Returns 0 on success, after
+// which the caller must call release_battery_properties.
+static int props_from_plist(c_battery_props *props, const void *bytes, long length) {
+    CFDataRef data = CFDataCreate(kCFAllocatorDefault, (const UInt8 *)bytes, length);
+    if (data == NULL) return 1;
+    CFPropertyListRef plist = CFPropertyListCreateWithData(kCFAllocatorDefault, data, 0, NULL, NULL);
+    CFRelease(data);
+    if (plist == NULL) return 1;
+    if (CFGetTypeID(plist) != CFDictionaryGetTypeID()) {
+        CFRelease(plist);
+        return 1;
+    }
+    props->properties = (void *)plist;
+    return 0;
+}
+
+// Decodes the requested sections of props into a scratch struct and frees it
+// again.
+static void decode_and_free(const c_battery_props *props, int sections) {
+    c_battery_info info = {0};
+    decode_battery_info(&info, props, sections);
+    free_battery_info(&info);
+}
+
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+	"unsafe"
+)
+
+// readBatteryInfo performs the IOKit read and translates the result into a
+// BatteryInfo with only the raw fields populated. Fields outside the
+// requested sections are left zero. Derived metrics are left to the caller so
+// the same calculation path can run on data from any source.
+//
+// Strings that match the previous read recorded in cache are reused rather
+// than copied again; a nil cache copies every string. Without
+// sectionIdentity, the identity strings are taken from cache as they are.
+func readBatteryInfo(opts Options, sections readSections, cache *stringCache) (*BatteryInfo, error) {
+	var c_info C.c_battery_info
+
+	// Call the C function.
+	start := time.Now()
+	ret := C.get_battery_info(&c_info, C.int(sections))
+	readAt := time.Now()
+	defer C.free_battery_info(&c_info)
+	if ret != 0 {
+		return nil, &ioKitError{code: int(ret)}
+	}
+
+	info, err := batteryInfoFromC(&c_info, opts, sections, cache)
+	info.ReadAt = readAt
+	info.ReadLatency = readAt.Sub(start)
+	return info, err
+}
+
+// readBatteryInfoStaged copies the battery properties once and then decodes
+// stages from that one copy in order, so every field comes from the same
+// instant. Before each stage after the first it checks deadline, and once
+// that has passed it stops; complete reports whether every stage was
+// decoded. The snapshot has only the raw fields populated, as with
+// readBatteryInfo.
+func readBatteryInfoStaged(stages []readSections, deadline time.Time) (info *BatteryInfo, complete bool, err error) {
+	var all readSections
+	for _, sections := range stages {
+		all |= sections
+	}
+
+	var props C.c_battery_props
+	start := time.Now()
+	ret := C.copy_battery_properties(&props, C.int(all))
+	readAt := time.Now()
+	if ret != 0 {
+		return nil, false, &ioKitError{code: int(ret)}
+	}
+	defer C.release_battery_properties(&props)
+
+	info, complete = decodeStaged(&props, stages, deadline)
+	info.ReadAt = readAt
+	info.ReadLatency = readAt.Sub(start)
+	return info, complete, nil
+}
+
+// decodeStaged decodes stages of props in order, stopping before any stage
+// after the first once deadline has passed.
+func decodeStaged(props *C.c_battery_props, stages []readSections, deadline time.Time) (info *BatteryInfo, complete bool) {
+	var c_info C.c_battery_info
+	defer C.free_battery_info(&c_info)
+
+	var read readSections
+	complete = true
+	for i, sections := range stages {
+		if i > 0 && !time.Now().Before(deadline) {
+			complete = false
+			break
+		}
+		C.decode_battery_info(&c_info, props, C.int(sections))
+		read |= sections
+	}
+	info, _ = batteryInfoFromC(&c_info, Options{}, read, nil)
+	return info, complete
+}
+
+// batteryInfoFromC translates the C struct, populated for sections, into our
+// public Go struct. This is where we also perform unit conversions (e.g.,
+// mV -> V). With opts.Strict, it also returns an error listing the expected
+// keys that were missing. A nil cache copies every string.
+func batteryInfoFromC(c_info *C.c_battery_info, opts Options, sections readSections, cache *stringCache) (*BatteryInfo, error) {
+	if cache == nil {
+		cache = &stringCache{}
+	}
+	info := &BatteryInfo{
+		State: State{
+			IsCharging:              c_info.is_charging != 0,
+			IsConnected:             c_info.is_connected != 0,
+			FullyCharged:            c_info.is_fully_charged != 0,
+			OptimizedChargingActive: c_info.is_optimized_charging != 0,
+			BatteryInstalled:        c_info.is_battery_installed != 0,
+			AtCriticalLevel:         c_info.is_at_critical_level != 0,
+		},
+		Battery: Battery{
+			ChemID:                  int(c_info.chem_id),
+			CycleCount:              int(c_info.cycle_count),
+			PermanentFailureStatus:  int(c_info.permanent_failure_status),
+			GaugeStatus:             int(c_info.gauge_status),
+			CalibrationNeeded:       c_info.gauge_status&gaugeConditionFlag != 0,
+			DesignCapacity:          int(c_info.design_capacity),
+			MaxCapacity:             int(c_info.max_capacity),
+			NominalCapacity:         int(c_info.nominal_capacity),
+			CurrentCapacity:         int(c_info.current_capacity),
+			TimeToEmpty:             int(c_info.time_to_empty),
+			TimeToFull:              int(c_info.time_to_full),
+			Temperature:             float64(c_info.temperature) / 100.0,
+			Voltage:                 float64(c_info.voltage) / 1000.0,
+			Amperage:                float64(c_info.amperage) / 1000.0,
+			TemperatureCentidegrees: int(c_info.temperature),
+			MaxChargeCurrent:        float64(c_info.max_charge_current) / 1000.0,
+			BatteryDataVoltage:      float64(c_info.battery_data_voltage) / 1000.0,
+			MaxDischargeCurrent:     math.Abs(float64(c_info.max_discharge_current) / 1000.0),
+			VoltageMV:               int(c_info.voltage),
+			CellVoltagesTruncated:   int(c_info.cell_voltage_total) > int(c_info.cell_voltage_count),
+			AmperageMA:              int(c_info.amperage),
+			TimeSinceFullCharge:     time.Duration(c_info.time_since_full_charge) * time.Second,
+		},
+		Adapter: Adapter{
+			Description:        cachedGoString(c_info.adapter_description, &cache.adapterDescription),
+			MaxWatts:           int(c_info.adapter_watts),
+			MaxVoltage:         float64(c_info.adapter_voltage) / 1000.0,
+			MaxAmperage:        float64(c_info.adapter_amperage) / 1000.0,
+			CableCurrentRating: float64(c_info.adapter_cable_current) / 1000.0,
+			Temperature:        float64(c_info.adapter_temperature) / 100.0,
+			InputVoltage:       float64(c_info.source_voltage) / 1000.0,
+			InputAmperage:      float64(c_info.source_amperage) / 1000.0,
+			IsWireless:         c_info.adapter_is_wireless != 0,
+			Role:               adapterRole(c_info.adapter_sinking != 0),
+			PDRevision:         cachedGoString(c_info.adapter_pd_revision, &cache.pdRevision),
+			SupportsPPS:        c_info.adapter_supports_pps != 0,
+		},
+		Charger: Charger{
+			ChargingVoltage:   float64(c_info.charging_voltage) / 1000.0,
+			ChargingCurrent:   float64(c_info.charging_current) / 1000.0,
+			NotChargingReason: int(c_info.not_charging_reason),
+			VacVoltageLimit:   float64(c_info.vac_voltage_limit) / 1000.0,
+		},
+		Capabilities: map[Capability]bool{
+			CapAdapterCableCurrentRating: c_info.has_adapter_cable_current != 0,
+			CapAdapterTemperature:        c_info.has_adapter_temperature != 0,
+			CapOptimizedCharging:         c_info.has_optimized_charging != 0,
+			CapGaugeStatus:               c_info.has_gauge_status != 0,
+			CapChargerData:               c_info.has_charger_data != 0,
+			CapMaxChargeCurrent:          c_info.has_max_charge_current != 0,
+			CapMaxDischargeCurrent:       c_info.has_max_discharge_current != 0,
+			CapTimeSinceFullCharge:       c_info.has_time_since_full_charge != 0,
+			CapAdapterIsWireless:         c_info.has_adapter_is_wireless != 0,
+			CapAdapterPDRevision:         c_info.adapter_pd_revision != nil,
+			CapAdapterPPS:                c_info.has_adapter_pps != 0,
+			CapBatteryDataVoltage:        c_info.has_battery_data_voltage != 0,
+		},
+	}
+
+	// Identity strings that weren't read keep the values cached from the
+	// last read that did.
+	if sections&sectionIdentity != 0 {
+		cachedGoString(c_info.serial_number, &cache.serialNumber)
+		cachedGoString(c_info.device_name, &cache.deviceName)
+		cachedGoString(c_info.firmware_version, &cache.firmwareVersion)
+		cachedGoString(c_info.manufacturer, &cache.manufacturer)
+
+		// Older gauges only report a numeric firmware revision.
+		if cache.firmwareVersion == "" && c_info.gas_gauge_firmware_version != 0 {
+			cache.firmwareVersion = fmt.Sprintf("%d", int(c_info.gas_gauge_firmware_version))
+		}
+	}
+	info.Battery.SerialNumber = cache.serialNumber
+	info.Battery.DeviceName = cache.deviceName
+	info.Battery.FirmwareVersion = cache.firmwareVersion
+	info.Battery.Manufacturer = cache.manufacturer
+
+	// Populate the adapter history if it is available.
+	if c_info.recent_adapter_count > 0 {
+		info.RecentAdapters = make([]Adapter, c_info.recent_adapter_count)
+		for i := range info.RecentAdapters {
+			a := &c_info.recent_adapters[i]
+			info.RecentAdapters[i] = Adapter{
+				Description: C.GoString(a.description),
+				MaxWatts:    int(a.watts),
+				MaxVoltage:  float64(a.voltage) / 1000.0,
+				MaxAmperage: float64(a.amperage) / 1000.0,
+				IsWireless:  a.is_wireless != 0,
+			}
+		}
+	}
+
+	// Populate the individual cell voltages if they are available.
+	if c_info.cell_voltage_count > 0 {
+		// Create a Go slice of the exact correct size.
+		info.Battery.IndividualCellVoltages = make([]int, c_info.cell_voltage_count)
+
+		// Unsafe cast to a Go-accessible array pointer. This is a standard CGO pattern.
+		c_voltages_ptr := &c_info.cell_voltages
+
+		// Copy the values from the C array to our new Go slice.
+		for i := 0; i < int(c_info.cell_voltage_count); i++ {
+			info.Battery.IndividualCellVoltages[i] = int(c_voltages_ptr[i])
+		}
+	}
+
+	if opts.Strict && c_info.missing != 0 {
+		return info, missingKeysError(int64(c_info.missing))
+	}
+	return info, nil
+}
+
+// cachedGoString returns the C string cs as a Go string, reusing *cached
+// without allocating when the two are equal, and otherwise copying cs and
+// storing the copy in *cached. A NULL cs reads as "".
+func cachedGoString(cs *C.char, cached *string) string {
+	if cs == nil {
+		*cached = ""
+		return ""
+	}
+	b := unsafe.Slice((*byte)(unsafe.Pointer(cs)), int(C.strlen(cs)))
+	if string(b) != *cached {
+		*cached = string(b)
+	}
+	return *cached
+}
+
+// strictKeys names the expected keys behind each MISSING_* bit in the C
+// code, indexed by bit position.
+var strictKeys = []string{
+	KeyBatteryData,
+	KeyAdapterDetails,
+	KeyPowerTelemetryData,
+	KeyCycleCount,
+	KeyDesignCapacity,
+	KeyAppleRawMaxCapacity,
+	KeyAppleRawCurrentCapacity,
+	KeyVoltage,
+	KeyAmperage,
+}
+
+// missingKeysError reports every key set in the missing bitmask.
+func missingKeysError(missing int64) error {
+	var errs []error
+	for bit, key := range strictKeys {
+		if missing&(1<<bit) != 0 {
+			errs = append(errs, &MissingKeyError{Key: key})
+		}
+	}
+	return &MultiError{Errors: errs}
+}
+
+// retainProbe holds the battery service and one copy of its properties so
+// that leak_darwin_test.go can check that reads leave their retain counts
+// unchanged.
+type retainProbe struct {
+	service C.io_service_t
+	props   C.c_battery_props
+}
+
+// retainCounts is a sample of the references held on the probed objects.
+type retainCounts struct {
+	Service, Properties, BatteryData int
+}
+
+// newRetainProbe matches the battery service and copies its properties. The
+// caller must call close.
+func newRetainProbe() (*retainProbe, error) {
+	var ret C.int
+	p := &retainProbe{service: C.match_battery_service(&ret)}
+	if p.service == 0 {
+		return nil, &ioKitError{code: int(ret)}
+	}
+	if ret = C.copy_service_properties(p.service, &p.props, C.int(sectionAll)); ret != 0 {
+		C.IOObjectRelease(p.service)
+		return nil, &ioKitError{code: int(ret)}
+	}
+	return p, nil
+}
+
+// counts samples the retain counts of the service and the properties.
+func (p *retainProbe) counts() retainCounts {
+	var c C.c_retain_counts
+	C.sample_retain_counts(p.service, &p.props, &c)
+	return retainCounts{
+		Service:     int(c.service),
+		Properties:  int(c.properties),
+		BatteryData: int(c.battery_data),
+	}
+}
+
+// decode parses every section of the held properties, as a read does, and
+// frees the result.
+func (p *retainProbe) decode() {
+	C.decode_and_free(&p.props, C.int(sectionAll))
+}
+
+// close releases the properties and the service.
+func (p *retainProbe) close() {
+	C.release_battery_properties(&p.props)
+	C.IOObjectRelease(p.service)
+}
+
+// decodeStagedPlist decodes stages of a plist of AppleSmartBattery
+// properties, as a budget read with an unlimited budget would, so that
+// budget_darwin_test.go can exercise the staged decoder without a battery.
+func decodeStagedPlist(plist []byte, stages []readSections) (*BatteryInfo, error) {
+	if len(plist) == 0 {
+		return nil, errors.New("power: empty property list")
+	}
+	var props C.c_battery_props
+	if C.props_from_plist(&props, unsafe.Pointer(&plist[0]), C.long(len(plist))) != 0 {
+		return nil, errors.New("power: property list is not a dictionary")
+	}
+	defer C.release_battery_properties(&props)
+	info, _ := decodeStaged(&props, stages, time.Now().Add(time.Hour))
+	return info, nil
+}
@@ -0,0 +1,55 @@
+package power
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// TimeRemainingUnknown is emitted by WatchTimeRemaining while macOS is
+	// still calculating an estimate (e.g., right after unplugging).
+	TimeRemainingUnknown time.Duration = -1
+
+	// TimeRemainingUnlimited is emitted by WatchTimeRemaining while the
+	// machine is running from external power.
+	TimeRemainingUnlimited time.Duration = -2
+)
+
+// WatchTimeRemaining emits macOS's battery time-remaining estimate, the same
+// one shown in the menu bar, whenever it changes. It is driven by the
+// IOPSNotificationCreateRunLoopSource notification rather than polling. The
+// current estimate is sent first; the channel is closed once ctx is done.
+//
+// Besides real durations, the channel carries TimeRemainingUnknown and
+// TimeRemainingUnlimited. If the consumer falls behind, stale estimates are
+// replaced by the latest one rather than queued.
+//
+// WatchTimeRemaining is safe for concurrent use; each call gets its own run
+// loop thread.
+func WatchTimeRemaining(ctx context.Context) (<-chan time.Duration, error) {
+	out := make(chan time.Duration, 1)
+	last := time.Duration(0)
+	first := true
+
+	publish := func() {
+		estimate := timeRemainingEstimate()
+		if !first && estimate == last {
+			return
+		}
+		first = false
+		last = estimate
+
+		// Replace an unread, stale estimate with the latest one.
+		select {
+		case <-out:
+		default:
+		}
+		out <- estimate
+	}
+
+	err := watchPowerSources(ctx, publish, func() { close(out) })
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,270 @@
+package power
+
+import "time"
+
+// BatteryInfo holds a comprehensive snapshot of all data points retrieved
+// from the AppleSmartBattery service in IOKit.
+type BatteryInfo struct {
+	// ReadAt is when the IOKit read completed, taken immediately after the
+	// CGO call returns.
+	ReadAt time.Time
+
+	// ReadLatency is how long the CGO call into IOKit took. Reads that
+	// start taking hundreds of milliseconds are an early sign of system
+	// stress.
+	ReadLatency time.Duration
+
+	State   State
+	Battery Battery
+	Adapter Adapter
+	Charger Charger
+
+	// RecentAdapters lists the adapters recently connected to this machine,
+	// from AppleRawAdapterDetails, for machines that keep that history.
+	// Only the negotiated rating fields (Description, MaxWatts, MaxVoltage,
+	// MaxAmperage, IsWireless) are filled in. It is nil when not reported.
+	RecentAdapters []Adapter
+
+	Calculations Calculations
+
+	// Capabilities records which optional data points this machine and
+	// adapter actually reported. A zero field is only meaningful when its
+	// capability is true.
+	Capabilities map[Capability]bool
+}
+
+// State holds booleans describing the current charging status.
+type State struct {
+	IsCharging   bool
+	IsConnected  bool
+	FullyCharged bool
+
+	// OptimizedChargingActive is true while macOS Optimized Battery Charging
+	// is holding the charge (typically at 80%) until it predicts the machine
+	// will be unplugged.
+	OptimizedChargingActive bool
+
+	// There is no charge limit here. A ceiling set by the user or an MDM
+	// profile belongs to the power management daemon, and none of the keys
+	// on AppleSmartBattery, its AppleSmartBatteryManager parent or
+	// ChargerData carries it. Optimized Battery Charging's hold shows up in
+	// OptimizedChargingActive instead.
+
+	// CalibrationInProgress is true while charging is held off on external
+	// power, short of full, with the gauge requesting a calibration cycle
+	// (Battery.CalibrationNeeded) and Optimized Battery Charging not
+	// engaged. AppleSmartBattery has no dedicated key for this, so it is
+	// inferred from those fields; see BatteryInfo.ChargeInhibitReason.
+	CalibrationInProgress bool
+
+	// BatteryInstalled and AtCriticalLevel are reported by either the battery
+	// or its AppleSmartBatteryManager parent.
+	BatteryInstalled bool
+	AtCriticalLevel  bool
+}
+
+// Battery contains all data points directly related to the battery itself,
+// from its hardware identifiers to its live electrical state.
+type Battery struct {
+	// Identity
+	SerialNumber    string
+	DeviceName      string
+	FirmwareVersion string // gas-gauge firmware, from FirmwareVersion or GasGaugeFirmwareVersion
+	ChemID          int    // cell chemistry identifier programmed into the gauge
+	Manufacturer    string // pack manufacturer code (e.g., "SMP"), when reported
+
+	// Health & Capacity
+	CycleCount             int
+	PermanentFailureStatus int  // non-zero when the gauge has latched a permanent failure
+	GaugeStatus            int  // raw gauge status word from BatteryData; bit meanings vary by chip
+	CalibrationNeeded      bool // gauge requests a full-discharge calibration cycle
+	DesignCapacity         int  // in mAh
+	MaxCapacity            int  // in mAh
+	NominalCapacity        int  // in mAh
+
+	// MaxCapacityFromPercent is true when the machine reported MaxCapacity
+	// as a percentage of DesignCapacity rather than in mAh, as some Intel
+	// models do, and MaxCapacity has been converted to mAh from it.
+	MaxCapacityFromPercent bool
+
+	// Live Charge & Readings
+	CurrentCapacity        int     // in mAh
+	TimeToEmpty            int     // in minutes
+	TimeToFull             int     // in minutes
+	Temperature            float64 // in Celsius
+	Voltage                float64 // in Volts
+	Amperage               float64 // in Amps (negative when discharging)
+	IndividualCellVoltages []int   // in mV
+
+	// There is no per-cell counterpart to Amperage. The cell blocks of a
+	// pack are wired in series, so they all carry the same pack current,
+	// and AppleSmartBattery doesn't report the small balancing currents the
+	// gauge bleeds from individual blocks. Cell-level health shows up in
+	// IndividualCellVoltages drift instead (see ConditionAdjustedHealth).
+
+	// CellVoltagesTruncated is true when the gauge reported more cell
+	// voltages than the reader has room for, in which case
+	// IndividualCellVoltages holds only the first ones.
+	CellVoltagesTruncated bool
+
+	// TemperatureCentidegrees is the raw gauge temperature in hundredths of
+	// a degree Celsius (e.g., 2998 for 29.98°C), for exact integer
+	// comparisons. Temperature carries the same value as a float.
+	TemperatureCentidegrees int
+
+	// MaxChargeCurrent is the highest charge current the gauge has recorded
+	// over the battery's lifetime, in Amps. It approximates the current
+	// delivered during the constant-current phase of charging. It is zero
+	// unless CapMaxChargeCurrent is set.
+	MaxChargeCurrent float64
+
+	// MaxDischargeCurrent is the highest discharge current the gauge has
+	// recorded over the battery's lifetime, in Amps, as a positive number.
+	// It is the closest thing to a discharge rating the pack reports. It is
+	// zero unless CapMaxDischargeCurrent is set.
+	MaxDischargeCurrent float64
+
+	// MaxChargePower bounds the most power the pack has accepted, in Watts,
+	// as MaxChargeCurrent × Charger.ChargingVoltage. The gauge records no
+	// rated or peak power of its own, and during the constant-current phase
+	// the pack sits below the charge voltage, so this is an upper-bound
+	// estimate rather than a measured peak. It is derived along with the
+	// Calculations, and is zero unless CapMaxChargePower is set.
+	MaxChargePower float64
+
+	// BatteryDataVoltage is the pack voltage as reported inside the nested
+	// BatteryData dictionary, in Volts. Voltage comes from the top-level key,
+	// which the battery driver updates on its own polling schedule, while
+	// BatteryData carries the gas gauge's latest reading, so the two can
+	// differ by a few millivolts. A larger disagreement points at one of the
+	// two sources being stale. It is zero unless CapBatteryDataVoltage is set.
+	BatteryDataVoltage float64
+
+	// VoltageMV and AmperageMA are the untouched register values behind
+	// Voltage and Amperage, in mV and mA. They are always in Apple's sign
+	// convention and are never smoothed, which makes them the ones to quote
+	// when reporting an odd power reading.
+	VoltageMV  int
+	AmperageMA int
+
+	// TimeSinceFullCharge is how long ago the battery was last fully
+	// charged, as tracked by the gauge. It is zero unless
+	// CapTimeSinceFullCharge is set.
+	TimeSinceFullCharge time.Duration
+}
+
+// Adapter holds information about the connected power source.
+type Adapter struct {
+	// Description is a system-provided string (e.g., "pd charger").
+	Description string
+
+	// MaxWatts is the negotiated power rating from the handshake (e.g., 96).
+	MaxWatts int
+
+	// MaxVoltage is the negotiated voltage from the handshake (e.g., 20.0V).
+	MaxVoltage float64
+
+	// MaxAmperage is the maximum current the adapter can provide at the
+	// negotiated voltage (e.g., 4.8A).
+	MaxAmperage float64
+
+	// CableCurrentRating is the current the attached cable is rated for, as
+	// reported by its e-marker (e.g., 3.0A or 5.0A). It is zero when the
+	// adapter doesn't report it. A 3A cable limits a 20V contract to 60W.
+	CableCurrentRating float64
+
+	// Temperature is the adapter's own temperature in Celsius, for adapters
+	// that report it. It is zero when not reported.
+	Temperature float64
+
+	// InputVoltage is the actual voltage being supplied by the adapter right now.
+	InputVoltage float64
+
+	// InputAmperage is the actual current being drawn by the system right now.
+	InputAmperage float64
+
+	// There is no power factor here. It describes the adapter's AC input,
+	// and AppleSmartBattery only sees the DC side: AdapterDetails reports
+	// the negotiated voltage, current and wattage, and PowerTelemetryData
+	// the DC input to the system. No key carries the AC real or apparent
+	// power a power factor would be computed from.
+
+	// IsWireless reports an inductive (wireless) power source, for adapters
+	// that say either way. It is false when not reported.
+	IsWireless bool
+
+	// Role is the power direction at the port: RoleSink while the adapter
+	// powers the Mac, or "" with no external power. AppleSmartBattery only
+	// sees power flowing into the machine, so a port sourcing power to a
+	// peripheral is not reported.
+	Role string
+
+	// PDRevision is the USB Power Delivery revision of the negotiated
+	// contract (e.g., "3.1"), for adapters that report it.
+	PDRevision string
+
+	// SupportsPPS reports whether the adapter offers a Programmable Power
+	// Supply contract, which lets the machine request fine-grained voltage
+	// steps instead of the fixed 5/9/15/20V levels. It is only meaningful
+	// when CapAdapterPPS is set.
+	SupportsPPS bool
+}
+
+// Charger holds the charging loop setpoints from the ChargerData dictionary.
+// Unlike Adapter, which describes what the power source can deliver, these
+// are what the charger is actually targeting, and explain tapering and
+// inhibited charging. All fields are zero unless CapChargerData is set.
+type Charger struct {
+	// ChargingVoltage is the voltage the charger is regulating the pack
+	// towards, in Volts.
+	ChargingVoltage float64
+
+	// ChargingCurrent is the current the charger is allowing into the pack,
+	// in Amps. It drops as the pack tapers towards full.
+	ChargingCurrent float64
+
+	// NotChargingReason is a raw bitmask of the reasons charging is being
+	// inhibited. Zero means nothing is holding charging back. The bits are
+	// undocumented; BatteryInfo.ChargeInhibitReason gives a decoded reason.
+	NotChargingReason int
+
+	// VacVoltageLimit is the input voltage limit the charger enforces on the
+	// adapter, in Volts.
+	VacVoltageLimit float64
+}
+
+// Calculations contains derived, user-friendly metrics.
+type Calculations struct {
+	// Health percentages
+	HealthByMaxCapacity     int
+	HealthByNominalCapacity int
+	ConditionAdjustedHealth int
+
+	// HealthByMaxCapacityFloat and HealthByNominalCapacityFloat are the same
+	// percentages as their int counterparts before rounding, for trend
+	// graphs that need to tell 90.4 from 90.6.
+	HealthByMaxCapacityFloat     float64
+	HealthByNominalCapacityFloat float64
+
+	// CyclesRemaining is how many cycles are left before the battery
+	// reaches its rated cycle count (see DesignCycleCount), floored at 0. It
+	// is -1 when the gauge chip has no known rating.
+	CyclesRemaining int
+
+	// Live power flow in Watts
+	ACPower      float64 // Power being drawn from the AC adapter.
+	BatteryPower float64 // Power flowing into(+) or out of(-) the battery.
+	SystemPower  float64 // Power being consumed by the rest of the system.
+
+	// SystemPowerAvailable is false when SystemPower couldn't be derived:
+	// the machine is on external power but reported no adapter input
+	// telemetry. SystemPower is 0 in that case.
+	SystemPowerAvailable bool
+
+	// amperageInverted records that Amperage and BatteryPower were negated by
+	// Options.AmperagePositiveWhenDischarging.
+	amperageInverted bool
+
+	// adapterWatts is Adapter.EffectiveWatts, for AdapterHeadroom.
+	adapterWatts int
+}
@@ -0,0 +1,64 @@
+//go:build !darwin || !cgo
+
+package power
+
+import (
+	"context"
+	"time"
+)
+
+// Stubs for the IOKit-backed hooks on platforms without IOKit or cgo. Every
+// live read fails with ErrUnsupported; everything that works on existing
+// snapshots is unaffected.
+
+func readBatteryInfo(Options, readSections, *stringCache) (*BatteryInfo, error) {
+	return nil, ErrUnsupported
+}
+
+func readSMCSystemPower() (float64, error) {
+	return 0, ErrUnsupported
+}
+
+func readBatteryInfoStaged([]readSections, time.Time) (*BatteryInfo, bool, error) {
+	return nil, false, ErrUnsupported
+}
+
+func watchPowerSources(context.Context, func(), func()) error {
+	return ErrUnsupported
+}
+
+func timeRemainingEstimate() time.Duration {
+	return TimeRemainingUnknown
+}
+
+func copyActiveAssertions() ([]Assertion, error) {
+	return nil, ErrUnsupported
+}
+
+func sysctlString(string) (string, error) {
+	return "", ErrUnsupported
+}
+
+func platformProductName() string {
+	return ""
+}
+
+func copyRegistryPropertiesXML(string) ([]byte, error) {
+	return nil, ErrUnsupported
+}
+
+func copyServicePropertiesXML(string) ([]byte, error) {
+	return nil, ErrUnsupported
+}
+
+func powerSourcesIsCharging() (bool, bool, error) {
+	return false, false, ErrUnsupported
+}
+
+func readChargeCapacities() (int, int, error) {
+	return 0, 0, ErrUnsupported
+}
+
+func bootTime() (time.Time, error) {
+	return time.Time{}, ErrUnsupported
+}
@@ -0,0 +1,40 @@
+package power
+
+import (
+	"fmt"
+	"math"
+)
+
+// polarityMinAmperage is the current, in Amps, below which Validate does not
+// trust the sign of the amperage. Near-idle readings hover around zero and
+// can point either way.
+const polarityMinAmperage = 0.05
+
+// Validate cross-checks two consecutive snapshots of the same battery. It
+// returns an error wrapping ErrAmperagePolarity if both report current
+// flowing in the same direction but CurrentCapacity moved the other way:
+// amperage says charging while the charge fell, or discharging while it rose.
+//
+// Pairs that can't be judged pass: nil snapshots, near-zero or disagreeing
+// amperage, unchanged capacity, or a gauge recalibration between the two
+// (see DetectRecalibration).
+func Validate(prev, cur *BatteryInfo) error {
+	if prev == nil || cur == nil || DetectRecalibration(prev, cur) {
+		return nil
+	}
+
+	before, after := prev.appleAmperage(), cur.appleAmperage()
+	if math.Abs(before) < polarityMinAmperage || math.Abs(after) < polarityMinAmperage ||
+		math.Signbit(before) != math.Signbit(after) {
+		return nil
+	}
+
+	delta := cur.Battery.CurrentCapacity - prev.Battery.CurrentCapacity
+	switch {
+	case after > 0 && delta < 0:
+		return fmt.Errorf("%w: charging at %.2fA but capacity fell by %d mAh", ErrAmperagePolarity, after, -delta)
+	case after < 0 && delta > 0:
+		return fmt.Errorf("%w: discharging at %.2fA but capacity rose by %d mAh", ErrAmperagePolarity, -after, delta)
+	}
+	return nil
+}
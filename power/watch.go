@@ -0,0 +1,77 @@
+package power
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// defaultWatchInterval applies when Watch or WatchSmoothed is given a
+// non-positive interval.
+const defaultWatchInterval = 5 * time.Second
+
+// Watch polls GetBatteryInfo every interval and emits each snapshot, starting
+// with one read immediately. A non-positive interval means five seconds.
+// Both channels are closed once ctx is done.
+//
+// Read failures are sent on the error channel instead of being dropped. If
+// the battery service disappears, ErrServiceVanished is sent once and Watch
+// keeps re-matching the service on every tick, resuming snapshots as soon as
+// it reappears. Other errors are sent each time they occur. The error channel
+// is buffered by one; errors that would block are dropped rather than
+// stalling the watcher. If the package is unsupported on this platform,
+// ErrUnsupported is sent and the channels are closed.
+func Watch(ctx context.Context, interval time.Duration) (<-chan *BatteryInfo, <-chan error) {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	out := make(chan *BatteryInfo)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(out)
+		defer close(errs)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		vanished := false
+		for {
+			info, err := GetBatteryInfo()
+			switch {
+			case err == nil:
+				vanished = false
+				select {
+				case out <- info:
+				case <-ctx.Done():
+					return
+				}
+			case errors.Is(err, ErrUnsupported):
+				errs <- err
+				return
+			case errors.Is(err, ErrServiceVanished):
+				// Report the disappearance once, not on every retry.
+				if !vanished {
+					vanished = true
+					sendError(errs, ErrServiceVanished)
+				}
+			default:
+				sendError(errs, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return out, errs
+}
+
+// sendError delivers err without blocking, dropping it if the consumer has
+// not drained the previous one.
+func sendError(errs chan<- error, err error) {
+	select {
+	case errs <- err:
+	default:
+	}
+}
@@ -0,0 +1,23 @@
+package power
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWatchNonPositiveInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		ctx, cancel := context.WithCancel(context.Background())
+		snapshots, errs := Watch(ctx, interval)
+		select {
+		case <-snapshots:
+		case <-errs:
+		case <-time.After(5 * time.Second):
+			t.Errorf("Watch(%v) produced neither a snapshot nor an error", interval)
+		}
+		cancel()
+		for range snapshots {
+		}
+	}
+}